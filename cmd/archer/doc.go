@@ -67,8 +67,16 @@ Use 'archer info <template>' to see the required variables for each template.
 Templates are YAML files defining the HTTP request for validation.
 See the examples/ directory and existing templates/ for reference.
 
-Custom templates can be used by placing them in the templates directory
-or by specifying a custom template path.
+Built-in templates are compiled into the binary. Custom templates are
+resolved from an ordered search path, where later entries override earlier
+ones with the same name:
+
+	~/.config/archer/templates    (or $XDG_CONFIG_HOME/archer/templates)
+	.archer/templates             (project-local)
+	$ARCHER_TEMPLATES_PATH        (path-list separated directories)
+	--templates-dir DIR           (repeatable)
+
+A single template file can also be used with --template-file.
 
 # Commands
 
@@ -1,15 +1,23 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/theinfosecguy/archer/internal/cli"
+	"github.com/theinfosecguy/archer/internal/constants"
+	archererrors "github.com/theinfosecguy/archer/internal/errors"
 )
 
 func main() {
 	if err := cli.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+
+		var exitErr *archererrors.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
+		os.Exit(constants.ExitCodeError)
 	}
 }
@@ -0,0 +1,9 @@
+// Package archer exposes the built-in validation templates shipped with Archer.
+package archer
+
+import "embed"
+
+// BuiltinTemplates holds the built-in YAML templates compiled into the binary
+//
+//go:embed templates templates/_*.yaml
+var BuiltinTemplates embed.FS
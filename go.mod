@@ -3,15 +3,16 @@ module github.com/theinfosecguy/archer
 go 1.25.0
 
 require (
+	github.com/PaesslerAG/gval v1.0.0
 	github.com/PaesslerAG/jsonpath v0.1.1
+	github.com/go-resty/resty/v2 v2.16.5
 	github.com/spf13/cobra v1.10.1
+	golang.org/x/net v0.33.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
-	github.com/PaesslerAG/gval v1.0.0 // indirect
-	github.com/go-resty/resty/v2 v2.16.5 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/spf13/pflag v1.0.9 // indirect
-	golang.org/x/net v0.33.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
 )
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+	archererrors "github.com/theinfosecguy/archer/internal/errors"
+	"github.com/theinfosecguy/archer/internal/models"
+	"github.com/theinfosecguy/archer/internal/templates"
+)
+
+var (
+	identifyValidate bool
+	identifyJSON     bool
+)
+
+var identifyCmd = &cobra.Command{
+	Use:   "identify [SECRET]",
+	Short: "List the templates whose secret format matches a secret",
+	Long: `List the templates whose secret format matches a secret.
+
+Every template on the search path that declares a secret_format is checked
+against the secret. Matching templates are ranked most specific first
+(longer prefixes and stricter checks rank higher). Nothing is sent over
+the network unless --validate is given.
+
+With --validate, the secret is validated against each candidate in turn
+until one reports valid.
+
+Examples:
+  export ARCHER_SECRET="sk_live_xxxxxxxxxxxxxxxx"
+  archer identify
+  archer identify --validate --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runIdentify,
+}
+
+func init() {
+	identifyCmd.Flags().BoolVar(&identifyValidate, "validate", false, "Validate against each candidate until one reports valid")
+	identifyCmd.Flags().BoolVar(&identifyJSON, "json", false, "Print the result as JSON")
+}
+
+func runIdentify(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	secret := os.Getenv(constants.EnvSecretName)
+	if secret == "" && len(args) > 0 {
+		secret = args[0]
+		fmt.Fprint(os.Stderr, constants.WarningSecretInCLI)
+	}
+	if secret == "" {
+		return errors.New("secret required. Provide via ARCHER_SECRET environment variable or command-line argument")
+	}
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	loader := newTemplateLoader()
+	candidates := loader.IdentifyTemplates(secret)
+
+	result := &models.IdentifyResultJSON{
+		Command:    "identify",
+		Version:    constants.Version,
+		Candidates: templateCandidates(candidates),
+	}
+
+	var validation *models.ValidationResult
+	if identifyValidate && len(candidates) > 0 {
+		v, err := newValidator(loader)
+		if err != nil {
+			return err
+		}
+		var candidate *templates.Candidate
+		validation, candidate, err = v.ValidateSecretAuto(ctx, candidates, secret)
+		if err != nil {
+			return err
+		}
+		result.Attempts = validation.Attempts
+		if validation.Valid {
+			result.MatchedTemplate = &candidate.Name
+		}
+	}
+
+	if identifyJSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		printIdentifyResult(result)
+	}
+
+	if len(candidates) == 0 {
+		return &archererrors.ExitError{
+			Code: constants.ExitCodeError,
+			Err:  fmt.Errorf("%s %s", constants.FailureIndicator, constants.NoTemplateMatched),
+		}
+	}
+	if validation != nil && !validation.Valid {
+		status := resultStatus(validation)
+		indicator := constants.FailureIndicator
+		if status == constants.StatusInconclusive {
+			indicator = constants.InconclusiveIndicator
+		}
+		return &archererrors.ExitError{
+			Code: exitCodeForStatus(status),
+			Err:  fmt.Errorf("%s %s%s", indicator, validation.Error, outcomeSuffix(validation)),
+		}
+	}
+	return nil
+}
+
+// templateCandidates converts ranked candidates for display and JSON output
+func templateCandidates(candidates []templates.Candidate) []models.TemplateCandidate {
+	result := make([]models.TemplateCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		result = append(result, models.TemplateCandidate{
+			Name:        candidate.Name,
+			Source:      candidate.Source.Name,
+			Score:       candidate.Score,
+			Description: candidate.Template.Description,
+		})
+	}
+	return result
+}
+
+// printIdentifyResult prints ranked candidates and any validation attempts
+func printIdentifyResult(result *models.IdentifyResultJSON) {
+	if len(result.Candidates) == 0 {
+		return
+	}
+
+	fmt.Printf("Candidate templates (%d):\n", len(result.Candidates))
+	for i, candidate := range result.Candidates {
+		fmt.Printf("  %d. %-15s [score %3d] [%-7s] - %s\n", i+1, candidate.Name, candidate.Score, candidate.Source, candidate.Description)
+	}
+
+	if len(result.Attempts) > 0 {
+		fmt.Println()
+		fmt.Println("Validation:")
+		for _, attempt := range result.Attempts {
+			if attempt.Outcome != "" {
+				fmt.Printf("  %s: %s (%s)\n", attempt.Template, attempt.Status, attempt.Outcome)
+			} else {
+				fmt.Printf("  %s: %s\n", attempt.Template, attempt.Status)
+			}
+		}
+	}
+	if result.MatchedTemplate != nil {
+		fmt.Printf("\n%s Secret is valid for template '%s'\n", constants.SuccessIndicator, *result.MatchedTemplate)
+	}
+}
@@ -2,10 +2,12 @@ package cli
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/spf13/cobra"
 
 	"github.com/theinfosecguy/archer/internal/constants"
+	"github.com/theinfosecguy/archer/internal/models"
 	"github.com/theinfosecguy/archer/internal/templates"
 	"github.com/theinfosecguy/archer/internal/variables"
 )
@@ -30,7 +32,7 @@ func init() {
 func runInfo(cmd *cobra.Command, args []string) error {
 	templateName := args[0]
 
-	loader := templates.NewTemplateLoader(constants.DefaultTemplatesDir)
+	loader := newTemplateLoader()
 	var templateIdentifier string
 	if infoTemplateFile != "" {
 		templateIdentifier = infoTemplateFile
@@ -38,13 +40,20 @@ func runInfo(cmd *cobra.Command, args []string) error {
 		templateIdentifier = templateName
 	}
 
-	template, err := loader.GetTemplate(templateIdentifier)
+	template, source, err := loader.ResolveTemplate(templateIdentifier)
 	if err != nil {
 		return fmt.Errorf("%s Template '%s' not found or invalid", constants.FailureIndicator, templateIdentifier)
 	}
 
 	fmt.Printf("Template: %s\n", template.Name)
 	fmt.Printf("Description: %s\n", template.Description)
+	fmt.Printf("Source: %s\n", source)
+	if shadowed := shadowedSources(loader, templateIdentifier, source); len(shadowed) > 0 {
+		fmt.Printf("Overrides: %s\n", joinStrings(shadowed, ", "))
+	}
+	if template.Extends != "" {
+		fmt.Printf("Extends: %s\n", template.Extends)
+	}
 	fmt.Printf("Mode: %s\n", template.Mode)
 	fmt.Printf("API URL: %s\n", template.APIURL)
 	fmt.Printf("Method: %s\n", template.Method)
@@ -65,6 +74,7 @@ func runInfo(cmd *cobra.Command, args []string) error {
 			for _, varName := range template.RequiredVariables {
 				cliName := variables.FormatVarNameForCLI(varName)
 				fmt.Printf("  %s (%s %s=<value>)\n", varName, constants.OptVar, cliName)
+				printVariableDetails(template, varName)
 			}
 		}
 		fmt.Println()
@@ -86,6 +96,42 @@ func runInfo(cmd *cobra.Command, args []string) error {
 		fmt.Println()
 	}
 
+	if len(template.OptionalVariables) > 0 {
+		fmt.Println("Optional Variables:")
+		for _, varName := range sortedKeys(template.OptionalVariables) {
+			cliName := variables.FormatVarNameForCLI(varName)
+			fmt.Printf("  %s (%s %s=<value>, default: %s)\n", varName, constants.OptVar, cliName, template.OptionalVariables[varName])
+			printVariableDetails(template, varName)
+		}
+		fmt.Println()
+	}
+
+	if len(template.Steps) > 0 {
+		fmt.Println("Steps:")
+		for i, step := range template.Steps {
+			fmt.Printf("  %d. %s: %s %s\n", i+1, step.Name, step.Method, variables.MaskVariables(step.APIURL))
+			names := make([]string, 0, len(step.Extract))
+			for name := range step.Extract {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				extraction := step.Extract[name]
+				fmt.Printf("     ${%s} <- %s\n", name, extraction.Source())
+			}
+		}
+		fmt.Printf("  %d. main request\n", len(template.Steps)+1)
+		fmt.Println()
+	}
+
+	if template.SecretFormat != nil {
+		fmt.Println("Secret Format:")
+		for _, line := range template.SecretFormat.Describe() {
+			fmt.Printf("  %s\n", line)
+		}
+		fmt.Println()
+	}
+
 	fmt.Println("Request Headers:")
 	for key, value := range template.Request.Headers {
 		maskedValue := variables.MaskVariables(value)
@@ -103,28 +149,199 @@ func runInfo(cmd *cobra.Command, args []string) error {
 
 	fmt.Println()
 	fmt.Printf("Timeout: %ds\n", template.Request.Timeout)
+	if template.NoProxy {
+		fmt.Println("Proxy: bypassed (no_proxy)")
+	}
 	fmt.Println()
 
 	fmt.Println("Success Criteria:")
-	fmt.Printf("  Status Codes: %v\n", template.SuccessCriteria.StatusCode)
+	fmt.Printf("  Status Codes: %s\n", joinStatusPatterns(template.SuccessCriteria.StatusCode))
 	if len(template.SuccessCriteria.RequiredFields) > 0 {
 		fmt.Printf("  Required Fields: %s\n", joinStrings(template.SuccessCriteria.RequiredFields, ", "))
 	}
+	if len(template.SuccessCriteria.FieldAssertions) > 0 {
+		fmt.Println("  Field Assertions:")
+		for _, assertion := range template.SuccessCriteria.FieldAssertions {
+			fmt.Printf("    %s\n", assertion)
+		}
+	}
+	if template.SuccessCriteria.ResponseFormat != "" {
+		fmt.Printf("  Response Format: %s\n", template.SuccessCriteria.ResponseFormat)
+	}
+	if len(template.SuccessCriteria.RequiredHeaders) > 0 {
+		fmt.Printf("  Required Headers: %s\n", joinStrings(template.SuccessCriteria.RequiredHeaders, ", "))
+	}
+	if len(template.SuccessCriteria.BodyContains) > 0 {
+		fmt.Printf("  Body Contains: %s\n", joinStrings(template.SuccessCriteria.BodyContains, ", "))
+	}
+	if len(template.SuccessCriteria.BodyNotContains) > 0 {
+		fmt.Printf("  Body Not Contains: %s\n", joinStrings(template.SuccessCriteria.BodyNotContains, ", "))
+	}
+	if len(template.SuccessCriteria.BodyRegex) > 0 {
+		fmt.Printf("  Body Regex: %s\n", joinStrings(template.SuccessCriteria.BodyRegex, ", "))
+	}
+	if template.SuccessCriteria.Expression != "" {
+		fmt.Printf("  Expression: %s\n", template.SuccessCriteria.Expression)
+	}
+
+	if !template.FailureCriteria.IsEmpty() {
+		fmt.Println()
+		fmt.Println("Failure Criteria:")
+		if len(template.FailureCriteria.FieldsPresent) > 0 {
+			fmt.Printf("  Fields Present: %s\n", joinStrings(template.FailureCriteria.FieldsPresent, ", "))
+		}
+		if len(template.FailureCriteria.BodyContains) > 0 {
+			fmt.Printf("  Body Contains: %s\n", joinStrings(template.FailureCriteria.BodyContains, ", "))
+		}
+		if len(template.FailureCriteria.BodyRegex) > 0 {
+			fmt.Printf("  Body Regex: %s\n", joinStrings(template.FailureCriteria.BodyRegex, ", "))
+		}
+		if len(template.FailureCriteria.HeadersPresent) > 0 {
+			fmt.Printf("  Headers Present: %s\n", joinStrings(template.FailureCriteria.HeadersPresent, ", "))
+		}
+		if len(template.FailureCriteria.ErrorMessages) > 0 {
+			fmt.Println("  Error Messages:")
+			keys := make([]string, 0, len(template.FailureCriteria.ErrorMessages))
+			for key := range template.FailureCriteria.ErrorMessages {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				fmt.Printf("    %s: %s\n", key, template.FailureCriteria.ErrorMessages[key])
+			}
+		}
+	}
+
+	if len(template.Extract) > 0 {
+		fmt.Println()
+		fmt.Println("Extracted Values:")
+		names := make([]string, 0, len(template.Extract))
+		for name := range template.Extract {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			extraction := template.Extract[name]
+			if extraction.Mask {
+				fmt.Printf("  %s: %s (masked)\n", name, extraction.Source())
+			} else {
+				fmt.Printf("  %s: %s\n", name, extraction.Source())
+			}
+		}
+	}
+
+	if template.Scopes != nil {
+		fmt.Println()
+		fmt.Println("Scopes:")
+		fmt.Printf("  Header: %s\n", template.Scopes.Header)
+		if len(template.Scopes.RiskLevels) > 0 {
+			fmt.Println("  Risk Levels:")
+			scopes := make([]string, 0, len(template.Scopes.RiskLevels))
+			for scope := range template.Scopes.RiskLevels {
+				scopes = append(scopes, scope)
+			}
+			sort.Strings(scopes)
+			for _, scope := range scopes {
+				fmt.Printf("    %s: %s\n", scope, template.Scopes.RiskLevels[scope])
+			}
+		}
+		fmt.Printf("  Unlisted Scopes: %s\n", template.Scopes.UnlistedRisk())
+	}
+
+	if len(template.Capabilities) > 0 {
+		fmt.Println()
+		fmt.Println("Capabilities (--probe):")
+		for _, capability := range template.Capabilities {
+			fmt.Printf("  %s: %s %s\n", capability.Name, capability.Method, variables.MaskVariables(capability.APIURL))
+			if capability.Description != "" {
+				fmt.Printf("    %s\n", capability.Description)
+			}
+		}
+	}
+
+	if len(template.Outcomes) > 0 {
+		fmt.Println()
+		fmt.Println("Outcomes:")
+		for _, outcome := range template.Outcomes {
+			fmt.Printf("  %s [%s]: %s\n", outcome.Name, outcome.ResolvedStatus(), outcome.Conditions())
+			if outcome.Message != "" {
+				fmt.Printf("    %s\n", outcome.Message)
+			}
+		}
+	}
 
 	fmt.Println()
 	fmt.Println("Error Handling:")
 	fmt.Printf("  Max Retries: %d\n", template.ErrorHandling.MaxRetries)
-	fmt.Printf("  Retry Delay: %ds\n", template.ErrorHandling.RetryDelay)
+	fmt.Printf("  Retry Delay: %s\n", template.ErrorHandling.RetryDelay)
+	fmt.Printf("  Backoff: %s (max delay %s)\n", template.ErrorHandling.BackoffStrategy(), template.ErrorHandling.RetryDelayCap())
+	fmt.Printf("  Retry On: %s\n", joinStatusPatterns(template.ErrorHandling.RetryStatuses()))
 	if len(template.ErrorHandling.ErrorMessages) > 0 {
 		fmt.Println("  Error Messages:")
-		for code, message := range template.ErrorHandling.ErrorMessages {
-			fmt.Printf("    %d: %s\n", code, message)
+		for _, key := range models.SortedStatusPatterns(template.ErrorHandling.ErrorMessages) {
+			fmt.Printf("    %s: %s\n", key, template.ErrorHandling.ErrorMessages[key])
+		}
+	}
+
+	printTLSSettings(tlsOptions.Merge(template.TLS))
+
+	return nil
+}
+
+// printTLSSettings shows the TLS settings requests for the template will use,
+// combining the command-line flags with the template's tls block
+func printTLSSettings(settings models.TLSConfig) {
+	if settings.IsEmpty() {
+		return
+	}
+	fmt.Println()
+	fmt.Println("TLS:")
+	if settings.InsecureSkipVerify {
+		fmt.Printf("  %sCertificate Verification: DISABLED (--%s)%s\n", constants.ColorRed, constants.InsecureFlagName, constants.ColorReset)
+	}
+	if len(settings.CAFiles) > 0 {
+		fmt.Printf("  CA Files: %s\n", joinStrings(settings.CAFiles, ", "))
+	}
+	if settings.CertFile != "" {
+		keyFile := settings.KeyFile
+		if keyFile == "" {
+			keyFile = settings.CertFile
 		}
+		fmt.Printf("  Client Certificate: %s (key: %s)\n", settings.CertFile, keyFile)
+	}
+	if settings.MinVersion != "" {
+		fmt.Printf("  Minimum Version: %s\n", settings.MinVersion)
 	}
+	if len(settings.Pins) > 0 {
+		fmt.Println("  Pins:")
+		for _, pin := range settings.Pins {
+			fmt.Printf("    %s\n", pin)
+		}
+	}
+}
 
+// shadowedSources returns the lower-precedence layers that also provide the template
+func shadowedSources(loader *templates.TemplateLoader, templateName string, resolved templates.TemplateSource) []string {
+	if resolved.Name == constants.SourceFile {
+		return nil
+	}
+	for _, entry := range loader.ListTemplates() {
+		if entry.Name == templateName {
+			return entry.Shadowed
+		}
+	}
 	return nil
 }
 
+// joinStatusPatterns formats status patterns as a comma-separated list
+func joinStatusPatterns(patterns models.StatusPatterns) string {
+	strs := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		strs = append(strs, string(pattern))
+	}
+	return joinStrings(strs, ", ")
+}
+
 func joinStrings(strs []string, sep string) string {
 	result := ""
 	for i, s := range strs {
@@ -135,3 +352,18 @@ func joinStrings(strs []string, sep string) string {
 	}
 	return result
 }
+
+// printVariableDetails prints the description, constraints and secrecy declared for a variable
+func printVariableDetails(template *models.SecretTemplate, varName string) {
+	if spec, ok := template.Variables[varName]; ok {
+		if spec.Description != "" {
+			fmt.Printf("    %s\n", spec.Description)
+		}
+		if constraints := spec.Constraints(); constraints != "" {
+			fmt.Printf("    %s\n", constraints)
+		}
+	}
+	if !template.IsSecretVariable(varName) {
+		fmt.Println("    not secret (shown unmasked in logs and JSON output)")
+	}
+}
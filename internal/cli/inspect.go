@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+	archererrors "github.com/theinfosecguy/archer/internal/errors"
+	"github.com/theinfosecguy/archer/internal/inspect"
+	"github.com/theinfosecguy/archer/internal/models"
+	"github.com/theinfosecguy/archer/internal/output"
+	"github.com/theinfosecguy/archer/internal/templates"
+)
+
+var (
+	inspectJSON       bool
+	inspectOutputJSON string
+)
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect [SECRET]",
+	Short: "Analyse a secret offline without sending it anywhere",
+	Long: `Analyse a secret offline without sending it anywhere.
+
+Identifies the provider and token type from the secret's format and decodes
+what the format reveals:
+
+  GitHub  token type and the CRC32 checksum embedded in prefixed tokens
+  AWS     key type and the account ID encoded in access key IDs
+  Slack   bot, user, app and configuration tokens
+  Stripe  live, test and restricted keys
+  JWT     header and claims, including expiry (the signature is not verified)
+
+When a template can validate the detected token type, its name is shown.
+
+Exit codes:
+  0  the secret was recognised
+  1  the secret does not match a known format
+  2  the offline checks prove the secret unusable (bad checksum or expired)
+
+Examples:
+  export ARCHER_SECRET="ghp_xxxxxxxxxxxxxxxxxxxx"
+  archer inspect
+  archer inspect --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runInspect,
+}
+
+func init() {
+	inspectCmd.Flags().BoolVar(&inspectJSON, "json", false, "Print the report as JSON")
+	inspectCmd.Flags().StringVarP(&inspectOutputJSON, "output-json", "o", "", "Write the report to a JSON file")
+}
+
+func runInspect(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	secret := os.Getenv(constants.EnvSecretName)
+	if secret == "" && len(args) > 0 {
+		secret = args[0]
+		fmt.Fprint(os.Stderr, constants.WarningSecretInCLI)
+	}
+	if secret == "" {
+		return errors.New("secret required. Provide via ARCHER_SECRET environment variable or command-line argument")
+	}
+
+	report := inspect.Inspect(secret)
+	linkTemplate(newTemplateLoader(), report, secret)
+
+	result := &models.InspectResultJSON{
+		Command:     "inspect",
+		Version:     constants.Version,
+		TokenReport: *report,
+	}
+	if inspectOutputJSON != "" {
+		if err := output.WriteInspectJSONFile(inspectOutputJSON, result); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write JSON output: %v\n", err)
+		}
+	}
+	if inspectJSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		printTokenReport(report)
+	}
+
+	switch {
+	case !report.Detected:
+		return &archererrors.ExitError{
+			Code: constants.ExitCodeError,
+			Err:  fmt.Errorf("%s %s", constants.FailureIndicator, constants.InspectUnknownToken),
+		}
+	case report.IsInvalid():
+		return &archererrors.ExitError{
+			Code: constants.ExitCodeInvalid,
+			Err:  fmt.Errorf("%s %s", constants.FailureIndicator, constants.InspectTokenUnusable),
+		}
+	}
+	return nil
+}
+
+// linkTemplate names the template that validates a recognised secret: the most
+// specific template on the search path whose secret_format accepts it
+func linkTemplate(loader *templates.TemplateLoader, report *models.TokenReport, secret string) {
+	if !report.Detected {
+		return
+	}
+	if candidates := loader.IdentifyTemplates(secret); len(candidates) > 0 {
+		report.Template = candidates[0].Name
+	}
+}
+
+// printTokenReport prints a human-readable inspection report
+func printTokenReport(report *models.TokenReport) {
+	if !report.Detected {
+		return
+	}
+
+	fmt.Printf("Provider: %s\n", report.Provider)
+	fmt.Printf("Type: %s\n", report.Type)
+	if report.ChecksumValid != nil {
+		if *report.ChecksumValid {
+			fmt.Println("Checksum: valid")
+		} else {
+			fmt.Println("Checksum: invalid")
+		}
+	}
+	if report.ExpiresAt != nil {
+		state := "not expired"
+		if report.Expired != nil && *report.Expired {
+			state = "expired"
+		}
+		fmt.Printf("Expires: %s (%s)\n", report.ExpiresAt.Format(time.RFC3339), state)
+	}
+	if len(report.Details) > 0 {
+		fmt.Println("Details:")
+		for _, detail := range report.Details {
+			fmt.Printf("  %s: %s\n", detail.Name, detail.Value)
+		}
+	}
+	if report.Template != "" {
+		fmt.Printf("Template: %s (archer validate %s)\n", report.Template, report.Template)
+	} else {
+		fmt.Printf("Template: %s\n", constants.InspectNoTemplate)
+	}
+}
@@ -2,41 +2,39 @@ package cli
 
 import (
 	"fmt"
-	"sort"
 
 	"github.com/spf13/cobra"
 
 	"github.com/theinfosecguy/archer/internal/constants"
-	"github.com/theinfosecguy/archer/internal/templates"
 )
 
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all available templates",
-	Long: `List all available templates from the default templates directory.
+	Long: `List all available templates on the template search path.
 
-Displays all built-in templates with their mode indicators and descriptions.
+Displays all templates with their mode indicators, the search path layer
+they were loaded from, and descriptions. Templates in user, project or
+--templates-dir directories override built-in templates with the same name.
 Use this command to discover available validation templates before validation.`,
 	RunE: runList,
 }
 
 func runList(cmd *cobra.Command, args []string) error {
-	templateNames := templates.DiscoverTemplates(constants.DefaultTemplatesDir)
+	loader := newTemplateLoader()
+	entries := loader.ListTemplates()
 
-	if len(templateNames) == 0 {
+	if len(entries) == 0 {
 		fmt.Println("No templates found.")
 		return nil
 	}
 
-	fmt.Printf("Available templates (%d):\n\n", len(templateNames))
+	fmt.Printf("Available templates (%d):\n\n", len(entries))
 
-	loader := templates.NewTemplateLoader(constants.DefaultTemplatesDir)
-
-	sort.Strings(templateNames)
-	for _, name := range templateNames {
-		template, err := loader.GetTemplate(name)
+	for _, entry := range entries {
+		template, err := loader.GetTemplate(entry.Name)
 		if err != nil {
-			fmt.Printf("  %-15s [%-10s] - %s\n", name, "invalid", "[Invalid template]")
+			fmt.Printf("  %-15s [%-10s] [%-7s] - %s\n", entry.Name, "invalid", entry.Source.Name, "[Invalid template]")
 			continue
 		}
 
@@ -45,7 +43,7 @@ func runList(cmd *cobra.Command, args []string) error {
 			mode = constants.ModeSingle
 		}
 
-		fmt.Printf("  %-15s [%-10s] - %s\n", template.Name, mode, template.Description)
+		fmt.Printf("  %-15s [%-10s] [%-7s] - %s\n", template.Name, mode, entry.Source.Name, template.Description)
 	}
 
 	return nil
@@ -1,19 +1,22 @@
 package cli
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/theinfosecguy/archer/internal/constants"
+	archererrors "github.com/theinfosecguy/archer/internal/errors"
 	"github.com/theinfosecguy/archer/internal/logger"
 	"github.com/theinfosecguy/archer/internal/models"
 	"github.com/theinfosecguy/archer/internal/output"
-	"github.com/theinfosecguy/archer/internal/templates"
 	"github.com/theinfosecguy/archer/internal/validator"
 	"github.com/theinfosecguy/archer/internal/variables"
 )
@@ -25,6 +28,14 @@ var (
 	debug        bool
 	outputJSON   string
 	jsonOnly     bool
+	probe        bool
+	autoDetect   bool
+
+	// templateSource records the search path layer the template resolved from
+	templateSource string
+
+	// autoCandidates records the templates matched by --auto, in rank order
+	autoCandidates []string
 )
 
 var validateCmd = &cobra.Command{
@@ -51,9 +62,24 @@ Multipart mode:
   # Using --var flags (shows security warning)
   archer validate ghost --var base-url=https://myblog.com --var api-token=xxxxx
 
+Capability probing:
+  # Report what a valid secret can do (allowed / denied / error per capability)
+  archer validate github --probe
+
+Automatic template detection:
+  # Try every template whose secret_format matches, most specific first
+  export ARCHER_SECRET="sk_live_xxxxxxxxxxxxxxxx"
+  archer validate --auto
+
+Exit codes:
+  0  valid         the provider accepted the secret
+  1  error         validation could not be attempted (template or input problem)
+  2  invalid       the provider rejected the secret
+  3  inconclusive  timeout, network failure, rate limiting or server error
+
 Security:
   Environment variables prevent secrets from appearing in shell history and process lists.`,
-	Args: cobra.MinimumNArgs(1),
+	Args: validateArgs,
 	RunE: runValidate,
 }
 
@@ -64,6 +90,16 @@ func init() {
 	validateCmd.Flags().BoolVarP(&debug, "debug", "d", false, "Enable debug logging")
 	validateCmd.Flags().StringVarP(&outputJSON, "output-json", "o", "", "Write structured validation result to JSON file")
 	validateCmd.Flags().BoolVar(&jsonOnly, "json-only", false, "Suppress normal terminal success output when writing JSON")
+	validateCmd.Flags().BoolVar(&probe, "probe", false, "Run the template's capability probes after a successful validation")
+	validateCmd.Flags().BoolVar(&autoDetect, "auto", false, "Detect the template from the secret's format instead of naming it")
+}
+
+// validateArgs requires a template name unless --auto picks one, in which case only the secret may be given
+func validateArgs(cmd *cobra.Command, args []string) error {
+	if autoDetect {
+		return cobra.MaximumNArgs(1)(cmd, args)
+	}
+	return cobra.MinimumNArgs(1)(cmd, args)
 }
 
 func runValidate(cmd *cobra.Command, args []string) error {
@@ -80,6 +116,17 @@ func runValidate(cmd *cobra.Command, args []string) error {
 	logger.Info("Starting secret validation process")
 	startTime := time.Now().UTC()
 
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	if autoDetect {
+		var secret string
+		if len(args) > 0 {
+			secret = args[0]
+		}
+		return handleAutoMode(ctx, secret, startTime)
+	}
+
 	templateName := args[0]
 	var secret string
 	if len(args) > 1 {
@@ -87,7 +134,7 @@ func runValidate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Load template to determine mode
-	loader := templates.NewTemplateLoader(constants.DefaultTemplatesDir)
+	loader := newTemplateLoader()
 	var templateIdentifier string
 	if templateFile != "" {
 		templateIdentifier = templateFile
@@ -95,7 +142,7 @@ func runValidate(cmd *cobra.Command, args []string) error {
 		templateIdentifier = templateName
 	}
 
-	template, err := loader.GetTemplate(templateIdentifier)
+	template, source, err := loader.ResolveTemplate(templateIdentifier)
 	if err != nil {
 		errMsg := fmt.Sprintf("Template '%s' not found or invalid", templateIdentifier)
 		if outputJSON != "" {
@@ -104,20 +151,98 @@ func runValidate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("%s %s", constants.FailureIndicator, errMsg)
 	}
 
+	templateSource = source.Name
+	logger.Debug("Template '%s' resolved from %s", templateIdentifier, source)
+
 	// Create validator
-	v := validator.NewSecretValidator(constants.DefaultTemplatesDir)
+	v, err := newValidator(loader)
+	if err != nil {
+		if outputJSON != "" {
+			writeJSONError(outputJSON, templateName, templateFile, nil, nil, startTime, err.Error())
+		}
+		return fmt.Errorf("%s %v", constants.FailureIndicator, err)
+	}
 
 	// Validate based on mode
 	if template.Mode == constants.ModeSingle {
-		return handleSingleMode(v, templateIdentifier, template, secret, startTime)
+		return handleSingleMode(ctx, v, templateIdentifier, template, secret, startTime)
 	} else if template.Mode == constants.ModeMultipart {
-		return handleMultipartMode(v, templateIdentifier, template, secret, varArgs, startTime)
+		return handleMultipartMode(ctx, v, templateIdentifier, template, secret, varArgs, startTime)
 	}
 
 	return fmt.Errorf("unsupported template mode: %s", template.Mode)
 }
 
-func handleSingleMode(v *validator.SecretValidator, templateIdentifier string, template *models.SecretTemplate, secret string, startTime time.Time) error {
+func handleAutoMode(ctx context.Context, secret string, startTime time.Time) error {
+	if templateFile != "" {
+		return errors.New("--auto cannot be combined with --template-file")
+	}
+	if len(varArgs) > 0 {
+		return errors.New("--var arguments not allowed with --auto")
+	}
+
+	finalSecret := os.Getenv(constants.EnvSecretName)
+	usedCLI := false
+	if finalSecret == "" {
+		finalSecret = secret
+		usedCLI = secret != ""
+	}
+	if finalSecret == "" {
+		errMsg := "secret required. Provide via ARCHER_SECRET environment variable or command-line argument"
+		if outputJSON != "" {
+			writeJSONError(outputJSON, "", "", nil, nil, startTime, errMsg)
+		}
+		return errors.New(errMsg)
+	}
+
+	loader := newTemplateLoader()
+	candidates := loader.IdentifyTemplates(finalSecret)
+	autoCandidates = make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		autoCandidates = append(autoCandidates, candidate.Name)
+	}
+	logger.Info("Candidate templates: %s", strings.Join(autoCandidates, ", "))
+
+	if len(candidates) == 0 {
+		if outputJSON != "" {
+			writeJSONError(outputJSON, "", "", nil, nil, startTime, constants.NoTemplateMatched)
+		}
+		return &archererrors.ExitError{
+			Code: constants.ExitCodeError,
+			Err:  fmt.Errorf("%s %s", constants.FailureIndicator, constants.NoTemplateMatched),
+		}
+	}
+
+	if usedCLI {
+		fmt.Fprint(os.Stderr, constants.WarningSecretInCLI)
+	}
+
+	v, err := newValidator(loader)
+	if err != nil {
+		if outputJSON != "" {
+			writeJSONError(outputJSON, "", "", nil, nil, startTime, err.Error())
+		}
+		return fmt.Errorf("%s %v", constants.FailureIndicator, err)
+	}
+	result, candidate, err := v.ValidateSecretAuto(ctx, candidates, finalSecret)
+	if err != nil {
+		if outputJSON != "" {
+			writeJSONError(outputJSON, "", "", nil, nil, startTime, err.Error())
+		}
+		return err
+	}
+
+	templateSource = candidate.Source.Name
+	if !(outputJSON != "" && jsonOnly) {
+		fmt.Printf("Template: %s (auto-detected, %d candidate(s): %s)\n", candidate.Name, len(autoCandidates), strings.Join(autoCandidates, ", "))
+	}
+
+	vars := map[string]string{constants.SecretVariableName: finalSecret}
+	probeCapabilities(ctx, v, candidate.Template, result)
+	return handleValidationResult(result, candidate.Template, vars, startTime)
+}
+
+func handleSingleMode(ctx context.Context, v *validator.SecretValidator, templateIdentifier string, template *models.SecretTemplate, secret string, startTime time.Time) error {
 	// Priority 1: Check environment variable
 	envSecret := os.Getenv(constants.EnvSecretName)
 
@@ -138,7 +263,7 @@ func handleSingleMode(v *validator.SecretValidator, templateIdentifier string, t
 		return errors.New(errMsg)
 	}
 
-	if len(varArgs) > 0 {
+	if len(varArgs) > 0 && len(template.OptionalVariables) == 0 {
 		errMsg := "--var arguments not allowed in single mode"
 		if outputJSON != "" {
 			vars := map[string]string{constants.SecretVariableName: finalSecret}
@@ -147,15 +272,41 @@ func handleSingleMode(v *validator.SecretValidator, templateIdentifier string, t
 		return errors.New(errMsg)
 	}
 
-	// Show warning if secret was passed via CLI
-	if usedCLI {
-		fmt.Fprint(os.Stderr, constants.WarningSecretInCLI)
+	// Optional variables come from ARCHER_VAR_* environment variables, overridden by --var flags
+	optionalVars := getEnvVariables(sortedKeys(template.OptionalVariables))
+	if len(varArgs) > 0 {
+		parsedVars, err := variables.ParseVarArgs(varArgs)
+		if err != nil {
+			if outputJSON != "" {
+				vars := map[string]string{constants.SecretVariableName: finalSecret}
+				writeJSONError(outputJSON, templateIdentifier, templateFile, template, vars, startTime, err.Error())
+			}
+			return err
+		}
+		for name, value := range parsedVars {
+			optionalVars[name] = value
+		}
 	}
 
 	// Build variables map for metadata
 	vars := map[string]string{constants.SecretVariableName: finalSecret}
+	for name, value := range optionalVars {
+		vars[name] = value
+	}
 
-	result, err := v.ValidateSecret(templateIdentifier, finalSecret)
+	if err := checkVariableConstraints(template, vars); err != nil {
+		if outputJSON != "" {
+			writeJSONError(outputJSON, templateIdentifier, templateFile, template, vars, startTime, err.Error())
+		}
+		return err
+	}
+
+	// Show warning if secret was passed via CLI
+	if usedCLI {
+		fmt.Fprint(os.Stderr, constants.WarningSecretInCLI)
+	}
+
+	result, err := v.ValidateSecretWithVariables(ctx, templateIdentifier, finalSecret, optionalVars)
 	if err != nil {
 		if outputJSON != "" {
 			writeJSONError(outputJSON, templateIdentifier, templateFile, template, vars, startTime, err.Error())
@@ -163,10 +314,11 @@ func handleSingleMode(v *validator.SecretValidator, templateIdentifier string, t
 		return err
 	}
 
+	probeCapabilities(ctx, v, template, result)
 	return handleValidationResult(result, template, vars, startTime)
 }
 
-func handleMultipartMode(v *validator.SecretValidator, templateIdentifier string, template *models.SecretTemplate, secret string, varArgs []string, startTime time.Time) error {
+func handleMultipartMode(ctx context.Context, v *validator.SecretValidator, templateIdentifier string, template *models.SecretTemplate, secret string, varArgs []string, startTime time.Time) error {
 	if secret != "" {
 		errMsg := "secret argument not allowed in multipart mode. Use --var or ARCHER_VAR_* environment variables instead"
 		if outputJSON != "" {
@@ -176,7 +328,7 @@ func handleMultipartMode(v *validator.SecretValidator, templateIdentifier string
 	}
 
 	// Try to get variables from environment first
-	envVars := getEnvVariables(template.RequiredVariables)
+	envVars := getEnvVariables(append(slices.Clone(template.RequiredVariables), sortedKeys(template.OptionalVariables)...))
 
 	var finalVars map[string]string
 	var usedCLI bool
@@ -188,7 +340,7 @@ func handleMultipartMode(v *validator.SecretValidator, templateIdentifier string
 			// All variables available from environment
 			finalVars = envVars
 		} else if len(varArgs) > 0 {
-			// Some missing from env, use CLI args
+			// Some missing from env, use CLI args on top of the environment
 			parsedVars, err := variables.ParseVarArgs(varArgs)
 			if err != nil {
 				if outputJSON != "" {
@@ -196,7 +348,10 @@ func handleMultipartMode(v *validator.SecretValidator, templateIdentifier string
 				}
 				return err
 			}
-			finalVars = parsedVars
+			finalVars = envVars
+			for name, value := range parsedVars {
+				finalVars[name] = value
+			}
 			usedCLI = true
 		} else {
 			errMsg := fmt.Sprintf("missing required variables: %s. Set via ARCHER_VAR_* environment variables or --var flags", strings.Join(missingVars, ", "))
@@ -224,12 +379,20 @@ func handleMultipartMode(v *validator.SecretValidator, templateIdentifier string
 		return errors.New(errMsg)
 	}
 
+	// Check variable types and patterns before any request is sent
+	if err := checkVariableConstraints(template, finalVars); err != nil {
+		if outputJSON != "" {
+			writeJSONError(outputJSON, templateIdentifier, templateFile, template, finalVars, startTime, err.Error())
+		}
+		return err
+	}
+
 	// Show warning if variables were passed via CLI
 	if usedCLI {
 		fmt.Fprint(os.Stderr, constants.WarningSecretInCLI)
 	}
 
-	result, err := v.ValidateSecretMultipart(templateIdentifier, finalVars)
+	result, err := v.ValidateSecretMultipart(ctx, templateIdentifier, finalVars)
 	if err != nil {
 		if outputJSON != "" {
 			writeJSONError(outputJSON, templateIdentifier, templateFile, template, finalVars, startTime, err.Error())
@@ -237,14 +400,52 @@ func handleMultipartMode(v *validator.SecretValidator, templateIdentifier string
 		return err
 	}
 
+	probeCapabilities(ctx, v, template, result)
 	return handleValidationResult(result, template, finalVars, startTime)
 }
 
+// checkVariableConstraints validates variable values against the template's
+// variable specifications, reporting every invalid variable at once
+func checkVariableConstraints(template *models.SecretTemplate, vars map[string]string) error {
+	errs := template.CheckVariables(vars)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Errorf(constants.InvalidVariables, strings.Join(messages, "\n  "))
+}
+
+// sortedKeys returns the keys of a variables map in name order
+func sortedKeys(vars map[string]string) []string {
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// probeCapabilities fills in the capability matrix when --probe is set and the secret is valid
+func probeCapabilities(ctx context.Context, v *validator.SecretValidator, template *models.SecretTemplate, result *models.ValidationResult) {
+	if !probe || !result.Valid {
+		return
+	}
+	if len(template.Capabilities) == 0 {
+		fmt.Fprintf(os.Stderr, constants.NoCapabilitiesDefined, template.Name)
+		return
+	}
+	result.Capabilities = v.ProbeCapabilities(ctx, template, result)
+}
+
 // getEnvVariables retrieves variables from ARCHER_VAR_* environment variables
-func getEnvVariables(requiredVariables []string) map[string]string {
+func getEnvVariables(variableNames []string) map[string]string {
 	envVars := make(map[string]string)
 
-	for _, varName := range requiredVariables {
+	for _, varName := range variableNames {
 		envKey := constants.EnvVarPrefix + varName
 		if value := os.Getenv(envKey); value != "" {
 			envVars[varName] = value
@@ -263,16 +464,137 @@ func handleValidationResult(result *models.ValidationResult, template *models.Se
 	}
 
 	// Handle terminal output
-	if result.Valid {
+	status := resultStatus(result)
+	if status == constants.StatusValid {
 		// Only show success message if not in json-only mode
 		if !(outputJSON != "" && jsonOnly) {
-			fmt.Printf("%s %s\n", constants.SuccessIndicator, result.Message)
+			fmt.Printf("%s %s%s\n", constants.SuccessIndicator, result.Message, outcomeSuffix(result))
+			printExtracted(result.Extracted)
+			printScopes(result.Scopes)
+			printCapabilities(result.Capabilities)
 		}
 		return nil
 	}
 
 	// Always show errors even in json-only mode
-	return fmt.Errorf("%s %s", constants.FailureIndicator, result.Error)
+	indicator := constants.FailureIndicator
+	if status == constants.StatusInconclusive {
+		indicator = constants.InconclusiveIndicator
+	}
+	return &archererrors.ExitError{
+		Code: exitCodeForStatus(status),
+		Err:  fmt.Errorf("%s %s%s", indicator, result.Error, outcomeSuffix(result)),
+	}
+}
+
+// printExtracted prints values extracted from the response in name order
+func printExtracted(extracted map[string]string) {
+	names := make([]string, 0, len(extracted))
+	for name := range extracted {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Printf("  %s: %s\n", name, extracted[name])
+	}
+}
+
+// printScopes prints the granted scopes with their risk levels and the computed privilege level
+func printScopes(report *models.ScopeReport) {
+	if report == nil {
+		return
+	}
+
+	scopes := make([]string, 0, len(report.Scopes))
+	for _, scope := range report.Scopes {
+		scopes = append(scopes, fmt.Sprintf("%s (%s)", scope.Name, scope.Risk))
+	}
+	if len(scopes) == 0 {
+		scopes = append(scopes, constants.RiskLevelNone)
+	}
+
+	fmt.Printf("  scopes: %s\n", strings.Join(scopes, ", "))
+	fmt.Printf("  privilege_level: %s\n", report.PrivilegeLevel)
+}
+
+// printCapabilities prints the capability matrix produced by --probe
+func printCapabilities(capabilities []models.CapabilityResult) {
+	if len(capabilities) == 0 {
+		return
+	}
+
+	width := 0
+	for _, capability := range capabilities {
+		width = max(width, len(capability.Name))
+	}
+
+	fmt.Println()
+	fmt.Println("Capabilities:")
+	for _, capability := range capabilities {
+		indicator := constants.CapabilityErrorIndicator
+		switch capability.Result {
+		case constants.CapabilityAllowed:
+			indicator = constants.CapabilityAllowedIndicator
+		case constants.CapabilityDenied:
+			indicator = constants.CapabilityDeniedIndicator
+		}
+
+		detail := capability.Description
+		if capability.Error != "" {
+			detail = capability.Error
+		}
+		fmt.Printf("  %-9s %-*s  %s\n", indicator, width, capability.Name, detail)
+	}
+}
+
+// outcomeSuffix returns the outcome annotation for terminal output, omitted for plain successes
+func outcomeSuffix(result *models.ValidationResult) string {
+	if result.Outcome == "" || result.Outcome == constants.OutcomeValid {
+		return ""
+	}
+	return fmt.Sprintf(" (outcome: %s)", result.Outcome)
+}
+
+// resultStatus returns the validation status, deriving it from Valid for results without one
+func resultStatus(result *models.ValidationResult) string {
+	if result.Status != "" {
+		return result.Status
+	}
+	if result.Valid {
+		return constants.StatusValid
+	}
+	return constants.StatusError
+}
+
+// responseStatusCode returns the status code of the last response to the main
+// request, or nil if it received none
+func responseStatusCode(result *models.ValidationResult) *int {
+	for i := len(result.RequestAttempts) - 1; i >= 0; i-- {
+		attempt := result.RequestAttempts[i]
+		if attempt.Step != "" {
+			break
+		}
+		if attempt.StatusCode != 0 {
+			return &attempt.StatusCode
+		}
+		return nil
+	}
+	return nil
+}
+
+// exitCodeForStatus maps a validation status to the process exit code
+func exitCodeForStatus(status string) int {
+	switch status {
+	case constants.StatusValid:
+		return constants.ExitCodeValid
+	case constants.StatusInvalid:
+		return constants.ExitCodeInvalid
+	case constants.StatusInconclusive:
+		return constants.ExitCodeInconclusive
+	default:
+		return constants.ExitCodeError
+	}
 }
 
 // writeJSONOutput writes successful validation result to JSON file
@@ -280,7 +602,7 @@ func writeJSONOutput(filepath string, result *models.ValidationResult, template
 	endTime := time.Now().UTC()
 
 	// Build masked artifacts
-	maskedURL, maskedHeaders := buildMaskedArtifacts(template, vars)
+	maskedURL, maskedHeaders, maskedQueryParams := buildMaskedArtifacts(template, vars)
 
 	// Get variable names (without values)
 	varNames := make([]string, 0, len(vars))
@@ -289,10 +611,7 @@ func writeJSONOutput(filepath string, result *models.ValidationResult, template
 	}
 
 	// Determine source
-	source := "builtin"
-	if templateFile != "" {
-		source = "file"
-	}
+	source := resolvedTemplateSource(templateFile)
 
 	// Build request metadata
 	requestMeta := models.ValidationRequestMeta{
@@ -303,8 +622,10 @@ func writeJSONOutput(filepath string, result *models.ValidationResult, template
 		Method:               &template.Method,
 		APIURLMasked:         &maskedURL,
 		HeadersMasked:        maskedHeaders,
-		QueryParamsMasked:    nil, // TODO: implement if needed
+		QueryParamsMasked:    maskedQueryParams,
 		VariablesProvided:    varNames,
+		AutoDetected:         autoDetect,
+		Candidates:           autoCandidates,
 		StartedAt:            startTime,
 		FinishedAt:           endTime,
 		DurationMS:           float64(endTime.Sub(startTime).Milliseconds()),
@@ -315,27 +636,66 @@ func writeJSONOutput(filepath string, result *models.ValidationResult, template
 	}
 
 	// Build response metadata
+	assertionsChecked := make([]string, 0, len(template.SuccessCriteria.FieldAssertions))
+	for _, assertion := range template.SuccessCriteria.FieldAssertions {
+		assertionsChecked = append(assertionsChecked, assertion.String())
+	}
+
 	responseMeta := models.ValidationResponseMeta{
-		StatusCode:            nil, // TODO: capture from HTTP response
+		StatusCode:            responseStatusCode(result),
 		RequiredFieldsChecked: template.SuccessCriteria.RequiredFields,
 		FailedRequiredField:   nil,
+		AssertionsChecked:     assertionsChecked,
 		Error:                 nil,
 	}
 
+	if !result.Valid {
+		responseMeta.Error = &result.Error
+	}
+	if result.FailedAssertion != "" {
+		responseMeta.FailedAssertion = &result.FailedAssertion
+	}
+	if result.MatchedFailure != "" {
+		responseMeta.MatchedFailure = &result.MatchedFailure
+	}
+	if result.FailedStep != "" {
+		responseMeta.FailedStep = &result.FailedStep
+	}
+	if len(result.Capabilities) > 0 {
+		responseMeta.Capabilities = result.Capabilities
+	}
+	if len(result.Attempts) > 0 {
+		responseMeta.Attempts = result.Attempts
+	}
+	if len(result.RequestAttempts) > 0 {
+		responseMeta.RequestAttempts = result.RequestAttempts
+	}
+	if len(result.Extracted) > 0 {
+		responseMeta.Extracted = result.Extracted
+	}
+	if result.Scopes != nil {
+		responseMeta.Scopes = result.Scopes.Scopes
+		responseMeta.PrivilegeLevel = &result.Scopes.PrivilegeLevel
+	}
+
 	// Build final JSON structure
 	jsonOutput := &models.ValidationResultJSON{
 		Command:  "validate",
 		Version:  constants.Version,
 		Valid:    result.Valid,
+		Status:   resultStatus(result),
 		Request:  requestMeta,
 		Response: responseMeta,
 	}
 
+	if result.Outcome != "" {
+		jsonOutput.Outcome = &result.Outcome
+	}
+
 	if result.Valid {
 		jsonOutput.Message = &result.Message
 	} else {
 		jsonOutput.Error = &result.Error
-		responseMeta.Error = &result.Error
 	}
 
 	return output.WriteJSONFile(filepath, jsonOutput)
@@ -359,22 +719,21 @@ func writeJSONError(filepath string, templateName string, templateFilePath strin
 	var source *string
 	var maskedURL *string
 	var maskedHeaders map[string]string
+	var maskedQueryParams map[string]string
 
 	if template != nil {
 		resolvedName = &template.Name
 		mode = &template.Mode
 		method = &template.Method
-		src := "builtin"
-		if templateFilePath != "" {
-			src = "file"
-		}
+		src := resolvedTemplateSource(templateFilePath)
 		source = &src
 
 		// Build masked artifacts if we have vars
 		if vars != nil {
-			url, headers := buildMaskedArtifacts(template, vars)
+			url, headers, queryParams := buildMaskedArtifacts(template, vars)
 			maskedURL = &url
 			maskedHeaders = headers
+			maskedQueryParams = queryParams
 		}
 	}
 
@@ -391,8 +750,10 @@ func writeJSONError(filepath string, templateName string, templateFilePath strin
 		Method:               method,
 		APIURLMasked:         maskedURL,
 		HeadersMasked:        maskedHeaders,
-		QueryParamsMasked:    nil,
+		QueryParamsMasked:    maskedQueryParams,
 		VariablesProvided:    varNames,
+		AutoDetected:         autoDetect,
+		Candidates:           autoCandidates,
 		StartedAt:            startTime,
 		FinishedAt:           endTime,
 		DurationMS:           float64(endTime.Sub(startTime).Milliseconds()),
@@ -408,6 +769,7 @@ func writeJSONError(filepath string, templateName string, templateFilePath strin
 		Command:  "validate",
 		Version:  constants.Version,
 		Valid:    false,
+		Status:   constants.StatusError,
 		Error:    &errorMsg,
 		Request:  requestMeta,
 		Response: responseMeta,
@@ -417,9 +779,22 @@ func writeJSONError(filepath string, templateName string, templateFilePath strin
 	_ = output.WriteJSONFile(filepath, jsonOutput)
 }
 
-// buildMaskedArtifacts builds masked URL and headers for JSON output
-func buildMaskedArtifacts(template *models.SecretTemplate, vars map[string]string) (string, map[string]string) {
-	_, maskedURL := variables.ProcessURL(template.APIURL, vars)
-	_, maskedHeaders := variables.ProcessHeaders(template.Request.Headers, vars)
-	return maskedURL, maskedHeaders
+// resolvedTemplateSource returns the search path layer name recorded in JSON output
+func resolvedTemplateSource(templateFilePath string) string {
+	if templateFilePath != "" {
+		return constants.SourceFile
+	}
+	if templateSource != "" {
+		return templateSource
+	}
+	return constants.SourceBuiltin
+}
+
+// buildMaskedArtifacts builds the masked URL, headers and query parameters for JSON output.
+// Only secret variables are masked; non-secret values such as base URLs are shown as provided.
+func buildMaskedArtifacts(template *models.SecretTemplate, vars map[string]string) (string, map[string]string, map[string]string) {
+	_, maskedURL := variables.ProcessURL(template.APIURL, vars, template.IsSecretVariable)
+	_, maskedHeaders := variables.ProcessHeaders(template.Request.Headers, vars, template.IsSecretVariable)
+	_, maskedQueryParams := variables.ProcessQueryParams(template.Request.QueryParams, vars, template.IsSecretVariable)
+	return maskedURL, maskedHeaders, maskedQueryParams
 }
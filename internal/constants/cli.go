@@ -2,15 +2,33 @@ package constants
 
 // CLI indicators
 const (
-	SuccessIndicator = "[SUCCESS]"
-	FailureIndicator = "[FAILED]"
-	OptVar           = "--var"
+	SuccessIndicator      = "[SUCCESS]"
+	FailureIndicator      = "[FAILED]"
+	InconclusiveIndicator = "[INCONCLUSIVE]"
+	OptVar                = "--var"
+	DeadlineFlagName      = "deadline"
+	ProxyFlagName         = "proxy"
+	CAFileFlagName        = "ca-file"
+	ClientCertFlagName    = "client-cert"
+	ClientKeyFlagName     = "client-key"
+	TLSMinVersionFlagName = "tls-min-version"
+	PinFlagName           = "pin"
+	InsecureFlagName      = "insecure-skip-verify"
+)
+
+// Process exit codes
+const (
+	ExitCodeValid        = 0 // Secret is valid
+	ExitCodeError        = 1 // Validation could not be attempted
+	ExitCodeInvalid      = 2 // Secret was rejected by the provider
+	ExitCodeInconclusive = 3 // Provider was unreachable or returned a transient error
 )
 
 // Environment variable names
 const (
 	EnvSecretName = "ARCHER_SECRET"
 	EnvVarPrefix  = "ARCHER_VAR_"
+	EnvProxy      = "ARCHER_PROXY"
 )
 
 // ANSI color codes
@@ -23,4 +41,13 @@ const (
 // Security warnings
 const (
 	WarningSecretInCLI = ColorRed + "[WARNING] Secrets passed as CLI arguments are exposed in shell history, process lists, and logs.\n" + ColorReset
+	WarningInsecureTLS = ColorRed + "[WARNING] TLS certificate verification is DISABLED (--insecure-skip-verify). Secrets can be intercepted by anyone on the network path.\n" + ColorReset
+)
+
+// Capability matrix indicators
+const (
+	CapabilityAllowedIndicator = "[ALLOWED]"
+	CapabilityDeniedIndicator  = "[DENIED]"
+	CapabilityErrorIndicator   = "[ERROR]"
+	NoCapabilitiesDefined      = "Template '%s' does not define capabilities to probe\n"
 )
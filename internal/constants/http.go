@@ -1,9 +1,84 @@
 package constants
 
+import (
+	"crypto/tls"
+	"time"
+)
+
 // HTTP defaults
 const (
 	MethodGet         = "GET"
+	MethodHead        = "HEAD"
+	MethodOptions     = "OPTIONS"
 	DefaultTimeout    = 30
 	DefaultMaxRetries = 0
 	DefaultRetryDelay = 0
 )
+
+// Retry backoff
+const (
+	BackoffFixed         = "fixed"       // Wait retry_delay before every retry
+	BackoffExponential   = "exponential" // Double the wait after every retry
+	BackoffJittered      = "jittered"    // Exponential, randomised between half and the full wait
+	DefaultMaxRetryDelay = 30            // Seconds; longer Retry-After requests are not waited for
+
+	HeaderRetryAfter     = "Retry-After"
+	HeaderRateLimitReset = "X-RateLimit-Reset"
+
+	// RateLimitResetEpoch separates X-RateLimit-Reset values given as Unix
+	// timestamps from those given as seconds to wait
+	RateLimitResetEpoch = 1_000_000_000
+)
+
+// BackoffStrategies lists the supported error_handling.backoff values
+var BackoffStrategies = map[string]bool{
+	BackoffFixed:       true,
+	BackoffExponential: true,
+	BackoffJittered:    true,
+}
+
+// RetryAfterStatuses are the status codes whose Retry-After and
+// X-RateLimit-Reset headers are honoured
+var RetryAfterStatuses = []int{429, 503}
+
+// Connection pool shared by every HTTP client in the process
+const (
+	MaxIdleConns        = 100
+	MaxIdleConnsPerHost = 10
+	IdleConnTimeout     = 90 * time.Second
+)
+
+// Proxy routing
+const (
+	RouteDirect        = "direct" // Route recorded for requests that bypass the proxy
+	DefaultProxyScheme = "http"   // Assumed when a proxy URL has no scheme
+)
+
+// ProxySchemes lists the supported proxy URL schemes
+var ProxySchemes = map[string]bool{
+	"http":    true,
+	"https":   true,
+	"socks5":  true,
+	"socks5h": true,
+}
+
+// TLS settings
+const (
+	TLSPinPrefix         = "sha256/" // SPKI pins are SHA-256 hashes, written as in HPKP
+	DefaultTLSMinVersion = "1.2"     // Go's client default; templates may only raise it
+)
+
+// TLSVersions maps the accepted tls.min_version values to protocol versions
+var TLSVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ReadOnlyMethods lists the HTTP methods capability probes may use
+var ReadOnlyMethods = map[string]bool{
+	MethodGet:     true,
+	MethodHead:    true,
+	MethodOptions: true,
+}
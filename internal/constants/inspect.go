@@ -0,0 +1,32 @@
+package constants
+
+// Token providers reported by archer inspect
+const (
+	ProviderGitHub = "github"
+	ProviderAWS    = "aws"
+	ProviderSlack  = "slack"
+	ProviderStripe = "stripe"
+	ProviderJWT    = "jwt"
+)
+
+// GitHub token layout: prefix, 30 random base62 characters, 6 character base62 CRC32 checksum
+const (
+	GitHubTokenRandomLength   = 30
+	GitHubTokenChecksumLength = 6
+	GitHubFineGrainedPrefix   = "github_pat_"
+	Base62Alphabet            = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// AWS access key IDs: 4 character prefix and 16 base32 characters encoding the account ID
+const (
+	AWSAccessKeyIDLength = 20
+	AWSAccountIDMask     = 0x7fffffffff80
+	AWSAccountIDShift    = 7
+)
+
+// Inspect messages
+const (
+	InspectUnknownToken  = "Secret does not match a known token format"
+	InspectNoTemplate    = "No validation template available"
+	InspectTokenUnusable = "Offline checks show the secret cannot be valid (bad checksum or expired)"
+)
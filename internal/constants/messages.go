@@ -7,24 +7,101 @@ const (
 
 // Error messages
 const (
-	TemplateNotFound      = "Template '%s' not found"
-	RequestTimeout        = "Request timeout"
-	RequestFailed         = "Request failed: %s"
-	InvalidJSONResponse   = "Invalid JSON response"
-	RequiredFieldNotFound = "Required field '%s' not found"
+	TemplateNotFound       = "Template '%s' not found"
+	RequestTimeout         = "Request timeout"
+	DeadlineExceeded       = "Deadline exceeded"
+	RequestCancelled       = "Request cancelled"
+	RequestFailed          = "Request failed: %s"
+	ProxyAuthRequired      = "Proxy authentication required"
+	TLSConfigFailed        = "TLS configuration error: %v"
+	InvalidJSONResponse    = "Invalid JSON response"
+	InvalidResponseBody    = "Invalid %s response"
+	RequiredHeaderNotFound = "Required header '%s' not found"
+	BodyMissingSubstring   = "Response body does not contain '%s'"
+	BodyHasSubstring       = "Response body contains '%s'"
+	BodyRegexNotMatched    = "Response body does not match /%s/"
+	RequiredFieldNotFound  = "Required field '%s' not found"
+	StepFailed             = "Step '%s' failed: %s"
+	StepValueNotFound      = "Step '%s' did not return a value for '%s'"
+	CapabilityUnexpected   = "Unexpected HTTP %d"
+	FieldAssertionFailed   = "Field assertion failed: %s"
+	ExpressionFailed       = "Success expression not satisfied: %s"
+	ExpressionEvalFailed   = "Success expression could not be evaluated: %v"
+	FailureCriteriaMatched = "Response matched failure criteria: %s"
+	OutcomeMatched         = "Response matched outcome '%s'"
+	SecretFormatInvalid    = "Secret format invalid: %s"
+	NoTemplateMatched      = "No template's secret_format matches the secret"
 )
 
 // Template validation messages
 const (
 	ModeValidationError        = "mode must be either 'single' or 'multipart'"
+	APIURLRequired             = "api_url is required"
 	MultipartRequiresVariables = "required_variables is mandatory when mode is 'multipart'"
 	SingleModeNoVariables      = "required_variables should not be specified when mode is 'single'"
 	MutualExclusionError       = "Cannot specify both 'data' and 'json_data'"
 	UpperSnakeCaseError        = "Variable '%s' must be in UPPER_SNAKE_CASE format"
 	SecretNotAllowedMultipart  = "${SECRET} is not allowed in multipart mode. Use custom variables instead."
-	InvalidVariablesSingle     = "In single mode, only ${SECRET} is allowed. Found: %s"
-	UndefinedVariables         = "Template uses undefined variables: %s. Add them to required_variables."
+	InvalidVariablesSingle     = "In single mode, only ${SECRET} and optional_variables are allowed. Found: %s"
+	UndefinedVariables         = "Template uses undefined variables: %s. Add them to required_variables or optional_variables."
 	UnusedRequiredVariables    = "Required variables not used in template: %s"
+	UnusedOptionalVariables    = "Optional variables not used in template: %s"
+	OptionalVariableReserved   = "SECRET cannot be declared in optional_variables"
+	OptionalVariableConflict   = "Variable '%s' cannot be both required and optional"
+	VariableSpecUndeclared     = "variables entry '%s' is not a required or optional variable"
+	VariableSpecInvalidType    = "variable '%s' has invalid type '%s' (expected url, hostname, uuid, email, integer or string)"
+	VariableSpecInvalidPattern = "variable '%s' has invalid pattern: %v"
+	OptionalDefaultInvalid     = "optional_variables default for '%s' is invalid: %v"
+	AssertionPathRequired      = "field_assertions entries must specify a path"
+	AssertionInvalidType       = "field assertion on '%s' has invalid type '%s' (expected bool, number, string, array or object)"
+	AssertionInvalidRegex      = "field assertion on '%s' has invalid regex: %v"
+	AssertionNoChecks          = "field assertion on '%s' does not specify any checks"
+	ResponseFormatInvalid      = "response_format '%s' is invalid (expected json, text, xml or form)"
+	ResponseFormatNoFields     = "required_fields and field_assertions need a json or form response_format, not '%s'"
+	SuccessInvalidRegex        = "success_criteria body_regex '%s' is invalid: %v"
+	BackoffInvalid             = "error_handling.backoff '%s' is invalid (expected fixed, exponential or jittered)"
+	RetryNegative              = "error_handling.%s must not be negative"
+	DelayInvalid               = "invalid delay '%s' (expected seconds or a duration such as 500ms)"
+	ProxyInvalid               = "invalid proxy URL (expected scheme://[user:password@]host:port)"
+	ProxySchemeInvalid         = "unsupported proxy scheme '%s' (expected http, https, socks5 or socks5h)"
+	TLSMinVersionInvalid       = "tls.min_version '%s' is invalid (expected 1.0, 1.1, 1.2 or 1.3)"
+	TLSPinInvalid              = "tls pin '%s' is invalid (expected sha256/ followed by a base64 SHA-256 hash)"
+	TLSKeyWithoutCert          = "tls.key_file requires tls.cert_file"
+	TLSCAFileInvalid           = "CA file '%s' contains no PEM certificates"
+	TLSClientCertInvalid       = "cannot load client certificate: %v"
+	TLSPinMismatch             = "certificate for %s does not match any configured pin"
+	ExpressionInvalid          = "success_criteria.expression is invalid: %v"
+	FailureInvalidRegex        = "failure_criteria body_regex '%s' is invalid: %v"
+	OutcomeInvalidName         = "outcome name '%s' must be in lower snake_case format"
+	OutcomeInvalidStatus       = "outcome '%s' has invalid status '%s' (expected valid, invalid or inconclusive)"
+	OutcomeInvalidRegex        = "outcome '%s' has invalid body_regex: %v"
+	OutcomeEqualsWithoutField  = "outcome '%s' specifies equals without field"
+	OutcomeNoConditions        = "outcome '%s' does not specify any conditions"
+	ExtractInvalidName         = "extract name '%s' must be in lower snake_case format"
+	ExtractSourceRequired      = "extract '%s' must specify exactly one of path or header"
+	ScopesHeaderRequired       = "scopes must specify the response header to read"
+	ScopesInvalidRiskLevel     = "scope '%s' has invalid risk level '%s' (expected low, medium, high or critical)"
+	ExtendsCycle               = "extends cycle detected: %s"
+	ExtendsParentNotFound      = "extended template '%s' not found"
+	ExtendsInvalid             = "extends must be a template name or file path"
+	StepInvalidName            = "step name '%s' must be in lower snake_case format"
+	StepURLRequired            = "step '%s' must specify api_url"
+	StepStatusCodeRequired     = "step '%s' must specify success_criteria.status_code"
+	StepVariableInvalidName    = "step '%s' extract name '%s' must be in UPPER_SNAKE_CASE format"
+	StepVariableConflict       = "step '%s' extract '%s' conflicts with an input variable"
+	StepVariableUndefined      = "step '%s' uses ${%s} before an earlier step extracts it"
+	CapabilityInvalidName      = "capability name '%s' must be in lower snake_case format"
+	CapabilityDuplicateName    = "capability '%s' is defined more than once"
+	CapabilityURLRequired      = "capability '%s' must specify api_url"
+	CapabilityMethodNotAllowed = "capability '%s' must use a read-only method (GET, HEAD or OPTIONS), got '%s'"
+	StatusPatternInvalid       = "invalid status code pattern '%s' (expected a code such as 404, a range such as 200-204 or a class such as 2xx, optionally negated with !)"
+	StatusPatternFieldInvalid  = "%s: %v"
+	SecretFormatNoChecks       = "secret_format does not specify any checks"
+	SecretFormatEmptyPrefix    = "secret_format prefixes must not be empty"
+	SecretFormatInvalidLength  = "secret_format lengths must be non-negative and min_length must not exceed max_length"
+	SecretFormatInvalidCharset = "secret_format has invalid charset '%s' (expected alphanumeric, numeric, hex, base64 or base64url)"
+	SecretFormatInvalidPattern = "secret_format has invalid pattern: %v"
+	SecretFormatMultipart      = "secret_format is only supported in single mode"
 )
 
 // CLI validation messages
@@ -34,12 +111,25 @@ const (
 	InvalidVariableFormat    = "Invalid variable format: '%s'. Use --var key=value"
 	InvalidKebabCase         = "Variable name '%s' must be in kebab-case format (e.g., 'api-token', 'base-url')"
 	VariableNotFound         = "Variable '${%s}' not found in provided variables"
+	VariableInvalidType      = "Variable '%s' must be a valid %s"
+	VariableInvalidURL       = "Variable '%s' must be an http or https URL with a host"
+	VariablePatternMismatch  = "Variable '%s' does not match pattern '%s'"
+	InvalidVariables         = "invalid variables:\n  %s"
+)
+
+// Secret format messages
+const (
+	SecretFormatPrefixMismatch  = "does not start with one of: %s"
+	SecretFormatTooShort        = "length %d is below the minimum of %d"
+	SecretFormatTooLong         = "length %d exceeds the maximum of %d"
+	SecretFormatCharsetMismatch = "contains characters outside the %s charset"
+	SecretFormatPatternMismatch = "does not match pattern '%s'"
 )
 
 // Logging messages
 const (
-	ValidatorInitialized      = "Validator initialized with templates directory: %s"
-	TemplateLoaderInitialized = "Template loader initialized with directory: %s"
+	ValidatorInitialized      = "Validator initialized with template sources: %v"
+	TemplateLoaderInitialized = "Template loader initialized with template sources: %v"
 	ValidationStarted         = "Starting validation for template '%s' in %s mode"
 	ValidationSuccess         = "Validation successful"
 	ValidationFailed          = "Validation failed: %s"
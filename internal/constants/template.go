@@ -8,9 +8,44 @@ const (
 
 // File system
 const (
-	DefaultTemplatesDir    = "templates"
+	BuiltinTemplatesDir    = "templates"
 	TemplateFileExtension  = ".yaml"
 	TemplateFileExtension2 = ".yml"
+	PartialTemplatePrefix  = "_" // Files such as _base.yaml are shared bases for extends, not templates
+)
+
+// Template inheritance
+const (
+	ExtendsKey = "extends"
+)
+
+// Template keys holding certificate paths, which are relative to the template file
+const (
+	TLSKey         = "tls"
+	TLSCAFilesKey  = "ca_files"
+	TLSCertFileKey = "cert_file"
+	TLSKeyFileKey  = "key_file"
+)
+
+// Template search path
+const (
+	UserConfigDir        = ".config"
+	UserTemplatesSubdir  = "archer/templates"
+	ProjectTemplatesDir  = ".archer/templates"
+	EnvXDGConfigHome     = "XDG_CONFIG_HOME"
+	EnvTemplatesPath     = "ARCHER_TEMPLATES_PATH"
+	TemplatesDirFlagName = "templates-dir"
+)
+
+// Template sources, in increasing order of precedence
+const (
+	SourceBuiltin   = "builtin"
+	SourceUser      = "user"
+	SourceProject   = "project"
+	SourceEnv       = "env"
+	SourceFlag      = "flag"
+	SourceDirectory = "directory"
+	SourceFile      = "file"
 )
 
 // File operations
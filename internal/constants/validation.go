@@ -16,6 +16,70 @@ var (
 	KebabCasePattern      = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
 )
 
+// Variable types
+const (
+	VariableTypeString   = "string"
+	VariableTypeURL      = "url"
+	VariableTypeHostname = "hostname"
+	VariableTypeUUID     = "uuid"
+	VariableTypeEmail    = "email"
+	VariableTypeInteger  = "integer"
+)
+
+// VariableTypes lists the supported variable types
+var VariableTypes = map[string]bool{
+	VariableTypeString:   true,
+	VariableTypeURL:      true,
+	VariableTypeHostname: true,
+	VariableTypeUUID:     true,
+	VariableTypeEmail:    true,
+	VariableTypeInteger:  true,
+}
+
+// Variable type patterns
+var (
+	HostnamePattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+	UUIDPattern     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// Secret format charsets
+const (
+	CharsetAlphanumeric = "alphanumeric"
+	CharsetNumeric      = "numeric"
+	CharsetHex          = "hex"
+	CharsetBase64       = "base64"
+	CharsetBase64URL    = "base64url"
+)
+
+// SecretCharsets maps each secret_format charset to the pattern its characters must match
+var SecretCharsets = map[string]*regexp.Regexp{
+	CharsetAlphanumeric: regexp.MustCompile(`^[A-Za-z0-9]*$`),
+	CharsetNumeric:      regexp.MustCompile(`^[0-9]*$`),
+	CharsetHex:          regexp.MustCompile(`^[0-9a-fA-F]*$`),
+	CharsetBase64:       regexp.MustCompile(`^[A-Za-z0-9+/]*={0,2}$`),
+	CharsetBase64URL:    regexp.MustCompile(`^[A-Za-z0-9_-]*$`),
+}
+
+// HTTP status code patterns
+const (
+	StatusPatternNegation = "!"
+	StatusRangeSeparator  = "-"
+	StatusClassSuffix     = "xx"
+	MinHTTPStatusCode     = 100
+	MaxHTTPStatusCode     = 599
+)
+
+// DefaultRetryOn lists the status patterns retried when a template does not set retry_on
+var DefaultRetryOn = []string{"429", "5xx"}
+
+// Secret format specificity weights used to rank auto-detected templates
+const (
+	SpecificityPrefixChar  = 10 // Per character of the matched prefix
+	SpecificityPattern     = 20
+	SpecificityCharset     = 5
+	SpecificityLengthBound = 2 // Per declared length bound
+)
+
 // Variable formatting
 const (
 	VariableSeparator      = "="
@@ -29,3 +93,143 @@ const (
 	MaskedVariablePrefix = "***"
 	MaskedVariableSuffix = "***"
 )
+
+// Field assertion types
+const (
+	AssertionTypeBool   = "bool"
+	AssertionTypeNumber = "number"
+	AssertionTypeString = "string"
+	AssertionTypeArray  = "array"
+	AssertionTypeObject = "object"
+)
+
+// AssertionTypes lists the supported field assertion types
+var AssertionTypes = map[string]bool{
+	AssertionTypeBool:   true,
+	AssertionTypeNumber: true,
+	AssertionTypeString: true,
+	AssertionTypeArray:  true,
+	AssertionTypeObject: true,
+}
+
+// Response formats
+const (
+	ResponseFormatJSON = "json" // Default; the body is decoded as JSON
+	ResponseFormatText = "text" // Plain text or HTML, checked only with body and header criteria
+	ResponseFormatXML  = "xml"  // The body must be well-formed XML
+	ResponseFormatForm = "form" // application/x-www-form-urlencoded; keys are addressable as $.key
+)
+
+// ResponseFormats lists the supported success_criteria.response_format values
+var ResponseFormats = map[string]bool{
+	ResponseFormatJSON: true,
+	ResponseFormatText: true,
+	ResponseFormatXML:  true,
+	ResponseFormatForm: true,
+}
+
+// Success expression parameters
+const (
+	ExpressionStatus  = "status"  // HTTP status code as a number
+	ExpressionHeaders = "headers" // Response headers keyed by lower-case name
+	ExpressionBody    = "body"    // Parsed JSON body, or the raw body when it is not JSON
+	ExpressionLen     = "len"     // Length of a string, array or object
+)
+
+// Validation statuses
+const (
+	StatusValid        = "valid"        // Provider confirmed the secret works
+	StatusInvalid      = "invalid"      // Provider rejected the secret
+	StatusInconclusive = "inconclusive" // Provider could not be reached or answered with a transient error
+	StatusError        = "error"        // Validation could not be attempted (template or input problem)
+)
+
+// ResponseStatuses lists the statuses a template outcome may resolve to
+var ResponseStatuses = map[string]bool{
+	StatusValid:        true,
+	StatusInvalid:      true,
+	StatusInconclusive: true,
+}
+
+// Outcome names
+const (
+	OutcomeValid                   = "valid"
+	OutcomeExpired                 = "expired"
+	OutcomeRevoked                 = "revoked"
+	OutcomeUnauthorized            = "unauthorized"
+	OutcomeInsufficientPermissions = "insufficient_permissions"
+	OutcomeNotFound                = "not_found"
+	OutcomeRateLimited             = "rate_limited"
+	OutcomeAccountSuspended        = "account_suspended"
+	OutcomeServerError             = "server_error"
+	OutcomeTimeout                 = "timeout"
+	OutcomeCancelled               = "cancelled"
+	OutcomeRequestFailed           = "request_failed"
+	OutcomeInvalidResponse         = "invalid_response"
+	OutcomeMissingField            = "missing_field"
+	OutcomeAssertionFailed         = "assertion_failed"
+	OutcomeExpressionFailed        = "expression_failed"
+	OutcomeMissingHeader           = "missing_header"
+	OutcomeBodyMismatch            = "body_mismatch"
+	OutcomeFailureCriteria         = "failure_criteria"
+	OutcomeFormatInvalid           = "format_invalid"
+	OutcomeHTTPStatus              = "http_%d"
+)
+
+// StatusCodeOutcomes names the outcome derived from error_messages for well-known status codes
+var StatusCodeOutcomes = map[int]string{
+	401: OutcomeUnauthorized,
+	403: OutcomeInsufficientPermissions,
+	404: OutcomeNotFound,
+	429: OutcomeRateLimited,
+	500: OutcomeServerError,
+	502: OutcomeServerError,
+	503: OutcomeServerError,
+	504: OutcomeServerError,
+}
+
+// SnakeCaseNamePattern matches lower snake_case names used for outcomes and extracted values
+var SnakeCaseNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Scope risk levels, ordered from least to most privileged
+const (
+	RiskLevelNone     = "none"
+	RiskLevelLow      = "low"
+	RiskLevelMedium   = "medium"
+	RiskLevelHigh     = "high"
+	RiskLevelCritical = "critical"
+)
+
+// RiskLevelRanks orders risk levels so the highest granted scope determines the privilege level
+var RiskLevelRanks = map[string]int{
+	RiskLevelNone:     0,
+	RiskLevelLow:      1,
+	RiskLevelMedium:   2,
+	RiskLevelHigh:     3,
+	RiskLevelCritical: 4,
+}
+
+// Scope parsing defaults
+const (
+	DefaultScopeSeparator = ","
+	DefaultScopeRisk      = RiskLevelMedium
+)
+
+// Capability probe results
+const (
+	CapabilityAllowed = "allowed"
+	CapabilityDenied  = "denied"
+	CapabilityError   = "error"
+)
+
+// Capability probe status code defaults
+var (
+	DefaultCapabilityAllowedStatus = []int{200}
+	DefaultCapabilityDeniedStatus  = []int{401, 403, 404}
+)
+
+// Extracted value masking
+const (
+	ExtractedMask          = "****"
+	ExtractedMaskKeepChars = 2
+)
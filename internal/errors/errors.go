@@ -80,3 +80,17 @@ type JSONWriteError struct {
 func (e *JSONWriteError) Error() string {
 	return fmt.Sprintf("failed to write JSON to '%s': %v", e.FilePath, e.Cause)
 }
+
+// ExitError wraps an error with the process exit code it should produce
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,127 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+
+	"github.com/PaesslerAG/jsonpath"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+	"github.com/theinfosecguy/archer/internal/models"
+)
+
+// checkFieldAssertion evaluates a single field assertion against parsed response data.
+// It returns a description of the failed check, or an empty string if the assertion passed.
+func checkFieldAssertion(assertion models.FieldAssertion, responseData any) string {
+	value, err := jsonpath.Get(assertion.Path, responseData)
+	if err != nil {
+		return fmt.Sprintf("'%s' not found", assertion.Path)
+	}
+
+	if assertion.Type != "" && jsonType(value) != assertion.Type {
+		return fmt.Sprintf("'%s' expected type %s, got %s", assertion.Path, assertion.Type, jsonType(value))
+	}
+
+	if assertion.Equals != nil && !valuesEqual(assertion.Equals, value) {
+		return fmt.Sprintf("'%s' expected to equal %v, got %v", assertion.Path, assertion.Equals, value)
+	}
+
+	if assertion.NotEquals != nil && valuesEqual(assertion.NotEquals, value) {
+		return fmt.Sprintf("'%s' expected not to equal %v", assertion.Path, assertion.NotEquals)
+	}
+
+	if assertion.Matches != "" {
+		pattern, err := regexp.Compile(assertion.Matches)
+		if err != nil || !pattern.MatchString(fmt.Sprint(value)) {
+			return fmt.Sprintf("'%s' expected to match /%s/, got %v", assertion.Path, assertion.Matches, value)
+		}
+	}
+
+	if assertion.Length != nil || assertion.MinLength != nil || assertion.MaxLength != nil {
+		length, ok := valueLength(value)
+		if !ok {
+			return fmt.Sprintf("'%s' has no length (type %s)", assertion.Path, jsonType(value))
+		}
+		if assertion.Length != nil && length != *assertion.Length {
+			return fmt.Sprintf("'%s' expected length %d, got %d", assertion.Path, *assertion.Length, length)
+		}
+		if assertion.MinLength != nil && length < *assertion.MinLength {
+			return fmt.Sprintf("'%s' expected length >= %d, got %d", assertion.Path, *assertion.MinLength, length)
+		}
+		if assertion.MaxLength != nil && length > *assertion.MaxLength {
+			return fmt.Sprintf("'%s' expected length <= %d, got %d", assertion.Path, *assertion.MaxLength, length)
+		}
+	}
+
+	if assertion.GreaterThan != nil || assertion.GreaterOrEq != nil || assertion.LessThan != nil || assertion.LessOrEq != nil {
+		number, ok := value.(float64)
+		if !ok {
+			return fmt.Sprintf("'%s' expected a number, got %s", assertion.Path, jsonType(value))
+		}
+		if assertion.GreaterThan != nil && !(number > *assertion.GreaterThan) {
+			return fmt.Sprintf("'%s' expected > %v, got %v", assertion.Path, *assertion.GreaterThan, number)
+		}
+		if assertion.GreaterOrEq != nil && !(number >= *assertion.GreaterOrEq) {
+			return fmt.Sprintf("'%s' expected >= %v, got %v", assertion.Path, *assertion.GreaterOrEq, number)
+		}
+		if assertion.LessThan != nil && !(number < *assertion.LessThan) {
+			return fmt.Sprintf("'%s' expected < %v, got %v", assertion.Path, *assertion.LessThan, number)
+		}
+		if assertion.LessOrEq != nil && !(number <= *assertion.LessOrEq) {
+			return fmt.Sprintf("'%s' expected <= %v, got %v", assertion.Path, *assertion.LessOrEq, number)
+		}
+	}
+
+	return ""
+}
+
+// jsonType returns the JSON type name of a decoded JSON value
+func jsonType(value any) string {
+	switch value.(type) {
+	case bool:
+		return constants.AssertionTypeBool
+	case float64:
+		return constants.AssertionTypeNumber
+	case string:
+		return constants.AssertionTypeString
+	case []any:
+		return constants.AssertionTypeArray
+	case map[string]any:
+		return constants.AssertionTypeObject
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// valueLength returns the length of an array, string or object value
+func valueLength(value any) (int, bool) {
+	switch v := value.(type) {
+	case []any:
+		return len(v), true
+	case string:
+		return len(v), true
+	case map[string]any:
+		return len(v), true
+	default:
+		return 0, false
+	}
+}
+
+// valuesEqual compares a template-supplied value with a decoded JSON value.
+// The expected value is round-tripped through JSON so YAML integers compare
+// equal to JSON numbers.
+func valuesEqual(expected any, actual any) bool {
+	data, err := json.Marshal(expected)
+	if err != nil {
+		return false
+	}
+	var normalized any
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(normalized, actual)
+}
@@ -0,0 +1,49 @@
+package http
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+	"github.com/theinfosecguy/archer/internal/logger"
+	"github.com/theinfosecguy/archer/internal/models"
+)
+
+// ProbeCapabilities runs the template capability probes and returns the
+// capability matrix. Steps are not run again: vars must already hold the
+// values they extracted, as recorded in ValidationResult.Variables.
+func (c *Client) ProbeCapabilities(
+	ctx context.Context,
+	template *models.SecretTemplate,
+	vars map[string]string,
+) []models.CapabilityResult {
+	results := make([]models.CapabilityResult, 0, len(template.Capabilities))
+
+	for i := range template.Capabilities {
+		capability := &template.Capabilities[i]
+		logger.Info("Probing capability '%s'", capability.Name)
+
+		result := models.CapabilityResult{
+			Name:        capability.Name,
+			Description: capability.Description,
+		}
+
+		resp, _, failure := c.sendRequest(ctx, capability.Template(template), vars)
+		if failure != nil {
+			result.Result = constants.CapabilityError
+			result.Error = failure.Error
+		} else {
+			statusCode := resp.StatusCode()
+			result.StatusCode = &statusCode
+			result.Result = capability.Classify(statusCode)
+			if result.Result == constants.CapabilityError {
+				result.Error = fmt.Sprintf(constants.CapabilityUnexpected, statusCode)
+			}
+		}
+
+		logger.Debug("Capability '%s': %s", capability.Name, result.Result)
+		results = append(results, result)
+	}
+
+	return results
+}
@@ -1,10 +1,15 @@
 package http
 
 import (
-	"encoding/json"
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
-	"strings"
-	"time"
+	"maps"
+	"net"
+	"net/http"
+	"os"
+	"sync"
 
 	"github.com/PaesslerAG/jsonpath"
 	"github.com/go-resty/resty/v2"
@@ -15,37 +20,206 @@ import (
 	"github.com/theinfosecguy/archer/internal/variables"
 )
 
-// Client wraps Resty client for API validation requests
+// Client sends validation requests. It holds no per-template state: timeouts
+// and retries are applied to each request, so one Client can be reused across
+// validations and shared between goroutines.
 type Client struct {
-	restyClient *resty.Client
+	restyClient  *resty.Client // Routed through the configured proxy, if any
+	directClient *resty.Client // Used by templates that set no_proxy
+	proxy        proxyFunc
+	tls          models.TLSConfig
+
+	mu         sync.Mutex
+	tlsClients map[string]*resty.Client // Clients for templates with their own tls settings
+}
+
+// ClientOptions configures how a Client reaches providers
+type ClientOptions struct {
+	// Proxy is an HTTP, HTTPS or SOCKS5 proxy URL, with optional
+	// user:password credentials, used instead of HTTP_PROXY and HTTPS_PROXY.
+	// Hosts listed in NO_PROXY are reached directly either way.
+	Proxy string
+
+	// TLS applies to every request; templates can add to it with their own
+	// tls settings
+	TLS models.TLSConfig
+}
+
+// sharedTransport and directTransport pool connections across every Client in
+// the process. The shared transport honours HTTP_PROXY, HTTPS_PROXY and NO_PROXY.
+var (
+	sharedTransport = newTransport(http.ProxyFromEnvironment, nil)
+	directTransport = newTransport(nil, nil)
+)
+
+// newTransport returns a copy of the default transport with a larger idle pool
+// and the given proxy selection and TLS settings
+func newTransport(proxy proxyFunc, tlsConfig *tls.Config) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	transport.TLSClientConfig = tlsConfig
+	transport.MaxIdleConns = constants.MaxIdleConns
+	transport.MaxIdleConnsPerHost = constants.MaxIdleConnsPerHost
+	transport.IdleConnTimeout = constants.IdleConnTimeout
+	return transport
 }
 
-// NewClient creates a new HTTP client
+// NewClient creates a new HTTP client backed by the shared connection pool,
+// using the proxy from the environment
 func NewClient() *Client {
+	return newClient(sharedTransport, directTransport, http.ProxyFromEnvironment, models.TLSConfig{})
+}
+
+// NewClientWithOptions creates a new HTTP client with the given options. An
+// explicit proxy or TLS settings get their own connection pool. CA bundles
+// and client certificates are loaded here, so missing files fail early.
+func NewClientWithOptions(options ClientOptions) (*Client, error) {
+	if options.Proxy == "" && options.TLS.IsEmpty() {
+		return NewClient(), nil
+	}
+
+	proxy := proxyFunc(http.ProxyFromEnvironment)
+	if options.Proxy != "" {
+		proxyURL, err := ParseProxy(options.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		proxy = fixedProxy(proxyURL)
+	}
+
+	tlsConfig, err := newTLSConfig(options.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	return newClient(newTransport(proxy, tlsConfig), newTransport(nil, tlsConfig), proxy, options.TLS), nil
+}
+
+// newClient creates a client sending proxied requests through transport and
+// the rest through direct
+func newClient(transport, direct *http.Transport, proxy proxyFunc, tlsOptions models.TLSConfig) *Client {
 	return &Client{
-		restyClient: resty.New(),
+		restyClient:  resty.NewWithClient(&http.Client{Transport: transport}),
+		directClient: resty.NewWithClient(&http.Client{Transport: direct}),
+		proxy:        proxy,
+		tls:          tlsOptions,
+		tlsClients:   make(map[string]*resty.Client),
 	}
 }
 
-// ExecuteRequest executes an HTTP request based on the template and variables
+// ExecuteRequest executes an HTTP request based on the template and variables.
+// If the template defines steps, they are executed first and their extracted
+// values are made available to the main request, and recorded in the result's
+// Variables for ProbeCapabilities. Cancelling ctx aborts the request in flight
+// and any pending retries.
 func (c *Client) ExecuteRequest(
+	ctx context.Context,
 	template *models.SecretTemplate,
 	vars map[string]string,
 ) (*models.ValidationResult, error) {
+	var history []models.RequestAttempt
+	if len(template.Steps) > 0 {
+		chainVars, stepAttempts, failure := c.executeSteps(ctx, template, vars)
+		history = append(history, stepAttempts...)
+		if failure != nil {
+			failure.RequestAttempts = history
+			return failure, nil
+		}
+		vars = chainVars
+	}
+
+	resp, attempts, failure := c.sendRequest(ctx, template, vars)
+	history = append(history, attempts...)
+	if failure != nil {
+		failure.RequestAttempts = history
+		return failure, nil
+	}
+
+	// Check response against success criteria
+	result, err := c.checkResponse(ctx, resp, template)
+	if result != nil {
+		result.RequestAttempts = history
+		result.Variables = vars
+	}
+	return result, err
+}
+
+// executeSteps runs the template steps in order, returning the variables for the
+// main request or the result of the first step that failed, along with every
+// attempt the steps made
+func (c *Client) executeSteps(
+	ctx context.Context,
+	template *models.SecretTemplate,
+	vars map[string]string,
+) (map[string]string, []models.RequestAttempt, *models.ValidationResult) {
+	chainVars := make(map[string]string, len(vars))
+	maps.Copy(chainVars, vars)
+	var history []models.RequestAttempt
+
+	for i := range template.Steps {
+		step := &template.Steps[i]
+		logger.Info("Executing step %d/%d: %s", i+1, len(template.Steps), step.Name)
+		stepTemplate := step.Template(template)
+
+		resp, attempts, failure := c.sendRequest(ctx, stepTemplate, chainVars)
+		for _, attempt := range attempts {
+			attempt.Step = step.Name
+			history = append(history, attempt)
+		}
+		if failure == nil {
+			failure, _ = c.checkResponse(ctx, resp, stepTemplate)
+			if failure.Valid {
+				failure = nil
+			}
+		}
+		if failure != nil {
+			failure.FailedStep = step.Name
+			failure.Error = fmt.Sprintf(constants.StepFailed, step.Name, failure.Error)
+			return nil, history, failure
+		}
+
+		extracted := extractValues(resp, step.Extract, stepTemplate.SuccessCriteria.Format())
+		for name := range step.Extract {
+			value, ok := extracted[name]
+			if !ok {
+				logger.Info("Step '%s' did not return '%s'", step.Name, name)
+				return nil, history, &models.ValidationResult{
+					Valid:      false,
+					Status:     constants.StatusInvalid,
+					Outcome:    constants.OutcomeMissingField,
+					Error:      fmt.Sprintf(constants.StepValueNotFound, step.Name, name),
+					FailedStep: step.Name,
+				}
+			}
+			chainVars[name] = value
+		}
+	}
+
+	return chainVars, history, nil
+}
+
+// sendRequest builds and executes the request described by the template,
+// returning the response and every attempt made. A non-nil result reports a
+// timeout or network failure.
+func (c *Client) sendRequest(
+	ctx context.Context,
+	template *models.SecretTemplate,
+	vars map[string]string,
+) (*resty.Response, []models.RequestAttempt, *models.ValidationResult) {
 	// Process URL
-	requestURL, maskedURL := variables.ProcessURL(template.APIURL, vars)
+	requestURL, maskedURL := variables.ProcessURL(template.APIURL, vars, template.IsSecretVariable)
 
 	// Process headers
-	requestHeaders, maskedHeaders := variables.ProcessHeaders(template.Request.Headers, vars)
+	requestHeaders, maskedHeaders := variables.ProcessHeaders(template.Request.Headers, vars, template.IsSecretVariable)
 
 	// Process query parameters
-	requestQueryParams, maskedQueryParams := variables.ProcessQueryParams(template.Request.QueryParams, vars)
+	requestQueryParams, maskedQueryParams := variables.ProcessQueryParams(template.Request.QueryParams, vars, template.IsSecretVariable)
 
 	// Process data
-	requestData, maskedData := variables.ProcessData(template.Request.Data, vars)
+	requestData, maskedData := variables.ProcessData(template.Request.Data, vars, template.IsSecretVariable)
 
 	// Process JSON data
-	requestJSONData, maskedJSONData := variables.ProcessJSONData(template.Request.JSONData, vars)
+	requestJSONData, maskedJSONData := variables.ProcessJSONData(template.Request.JSONData, vars, template.IsSecretVariable)
 
 	// Log request preparation with masked values
 	logger.Debug("Preparing %s request to %s", template.Method, maskedURL)
@@ -60,66 +234,47 @@ func (c *Client) ExecuteRequest(
 		logger.Debug("Request JSON data (masked): %v", maskedJSONData)
 	}
 
-	// Configure client with timeout and retries
-	restyClient := c.restyClient.
-		SetTimeout(time.Duration(template.Request.Timeout) * time.Second)
-
-	// Configure retries if specified
-	if template.ErrorHandling.MaxRetries > 0 {
-		restyClient.
-			SetRetryCount(template.ErrorHandling.MaxRetries).
-			SetRetryWaitTime(time.Duration(template.ErrorHandling.RetryDelay) * time.Second).
-			// Retry on 5xx server errors and 429 rate limiting
-			AddRetryCondition(func(r *resty.Response, err error) bool {
-				// Retry on network errors
-				if err != nil {
-					return true
-				}
-				// Retry on server errors (5xx) and rate limiting (429)
-				return r.StatusCode() >= 500 || r.StatusCode() == 429
-			})
-	}
-
-	// Create request
-	req := restyClient.R()
-
-	// Set headers
-	if len(requestHeaders) > 0 {
-		req.SetHeaders(requestHeaders)
-	}
-
-	// Set query parameters
-	if len(requestQueryParams) > 0 {
-		req.SetQueryParams(requestQueryParams)
+	// Build a fresh request for every attempt so no state carries over
+	configure := func(req *resty.Request) {
+		if len(requestHeaders) > 0 {
+			req.SetHeaders(requestHeaders)
+		}
+		if len(requestQueryParams) > 0 {
+			req.SetQueryParams(requestQueryParams)
+		}
+		if requestData != nil {
+			req.SetBody(*requestData)
+		} else if requestJSONData != nil {
+			req.SetBody(requestJSONData)
+		}
 	}
 
-	// Set body
-	if requestData != nil {
-		req.SetBody(*requestData)
-	} else if requestJSONData != nil {
-		req.SetBody(requestJSONData)
+	client, err := c.clientFor(template)
+	if err != nil {
+		logger.Info("Invalid TLS configuration: %v", err)
+		return nil, nil, &models.ValidationResult{
+			Valid:  false,
+			Status: constants.StatusError,
+			Error:  fmt.Sprintf(constants.TLSConfigFailed, err),
+		}
 	}
 
 	// Execute request
 	logger.Debug("Sending request (timeout: %ds)...", template.Request.Timeout)
-	resp, err := req.Execute(template.Method, requestURL)
+	resp, attempts, err := c.execute(ctx, client, template, requestURL, vars, configure)
 	if err != nil {
-		// Check if it's a timeout error
-		errMsg := err.Error()
-		if strings.Contains(errMsg, "context deadline exceeded") ||
-			strings.Contains(errMsg, "Client.Timeout exceeded") ||
-			strings.Contains(errMsg, "timeout") {
-			logger.Info("Request timeout after %ds", template.Request.Timeout)
-			return &models.ValidationResult{
-				Valid: false,
-				Error: constants.RequestTimeout,
-			}, nil
+		return nil, attempts, requestFailure(ctx, template, vars, err)
+	}
+
+	// A 407 comes from the proxy, not the provider, so it says nothing about the secret
+	if resp.StatusCode() == http.StatusProxyAuthRequired && attempts[len(attempts)-1].Route != constants.RouteDirect {
+		logger.Info("Proxy rejected the request: %s", constants.ProxyAuthRequired)
+		return nil, attempts, &models.ValidationResult{
+			Valid:   false,
+			Status:  constants.StatusInconclusive,
+			Outcome: constants.OutcomeRequestFailed,
+			Error:   constants.ProxyAuthRequired,
 		}
-		logger.Info("Request failed: %s", err.Error())
-		return &models.ValidationResult{
-			Valid: false,
-			Error: fmt.Sprintf(constants.RequestFailed, err.Error()),
-		}, nil
 	}
 
 	logger.Info("Request completed with status code: %d", resp.StatusCode())
@@ -133,50 +288,137 @@ func (c *Client) ExecuteRequest(
 		logger.Debug("Response content: %s", bodyStr)
 	}
 
-	// Check response against success criteria
-	return c.checkResponse(resp, template)
+	return resp, attempts, nil
+}
+
+// requestFailure classifies a transport error as a timeout, a cancellation or
+// a network failure. Every outcome is inconclusive: the provider never answered.
+// Secret values quoted in the error, such as in the request URL, are masked.
+func requestFailure(ctx context.Context, template *models.SecretTemplate, vars map[string]string, err error) *models.ValidationResult {
+	var netErr net.Error
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		logger.Info("Validation deadline exceeded")
+		return &models.ValidationResult{
+			Valid:   false,
+			Status:  constants.StatusInconclusive,
+			Outcome: constants.OutcomeTimeout,
+			Error:   constants.DeadlineExceeded,
+		}
+	case errors.Is(err, context.Canceled):
+		logger.Info("Request cancelled")
+		return &models.ValidationResult{
+			Valid:   false,
+			Status:  constants.StatusInconclusive,
+			Outcome: constants.OutcomeCancelled,
+			Error:   constants.RequestCancelled,
+		}
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		logger.Info("Request timeout after %ds", template.Request.Timeout)
+		return &models.ValidationResult{
+			Valid:   false,
+			Status:  constants.StatusInconclusive,
+			Outcome: constants.OutcomeTimeout,
+			Error:   constants.RequestTimeout,
+		}
+	default:
+		message := variables.MaskSecretValues(err.Error(), vars, template.IsSecretVariable)
+		logger.Info("Request failed: %s", message)
+		return &models.ValidationResult{
+			Valid:   false,
+			Status:  constants.StatusInconclusive,
+			Outcome: constants.OutcomeRequestFailed,
+			Error:   fmt.Sprintf(constants.RequestFailed, message),
+		}
+	}
 }
 
-// checkResponse validates the response against template success criteria
+// checkResponse validates the response against template success criteria.
+// ctx bounds the evaluation of the success expression.
 func (c *Client) checkResponse(
+	ctx context.Context,
 	resp *resty.Response,
 	template *models.SecretTemplate,
 ) (*models.ValidationResult, error) {
 	logger.Debug("Validating response against template success criteria")
 	statusCode := resp.StatusCode()
 
-	// Check status code
-	statusCodeValid := false
-	for _, code := range template.SuccessCriteria.StatusCode {
-		if statusCode == code {
-			statusCodeValid = true
-			break
+	// Check named outcomes first
+	if outcome := matchOutcome(resp, template.Outcomes, template.SuccessCriteria.Format()); outcome != nil {
+		logger.Info("Response matched outcome '%s'", outcome.Name)
+		result := outcomeResult(outcome)
+		if result.Valid {
+			result.Extracted = extractValues(resp, template.Extract, template.SuccessCriteria.Format())
+			result.Scopes = reportScopes(resp, template.Scopes)
 		}
+		return result, nil
 	}
 
-	if !statusCodeValid {
-		logger.Info("Status code validation failed: got %d, expected one of %v", statusCode, template.SuccessCriteria.StatusCode)
-		errorMsg := fmt.Sprintf("HTTP %d", statusCode)
-		if customMsg, ok := template.ErrorHandling.ErrorMessages[statusCode]; ok {
-			errorMsg = customMsg
+	// Check status code
+	if !template.SuccessCriteria.StatusCode.Matches(statusCode) {
+		logger.Info("Status code validation failed: got %d, expected %v", statusCode, template.SuccessCriteria.StatusCode)
+
+		// Outcomes derived from error_messages only apply to unsuccessful status codes
+		if outcome := matchOutcome(resp, template.ErrorOutcomes(), template.SuccessCriteria.Format()); outcome != nil {
+			matched := outcome.ForStatusCode(statusCode)
+			logger.Info("Response matched outcome '%s'", matched.Name)
+			return outcomeResult(&matched), nil
 		}
+
 		return &models.ValidationResult{
-			Valid: false,
-			Error: errorMsg,
+			Valid:   false,
+			Status:  models.StatusForHTTPCode(statusCode),
+			Outcome: models.OutcomeNameForStatusCode(statusCode),
+			Error:   fmt.Sprintf("HTTP %d", statusCode),
 		}, nil
 	}
 
 	logger.Debug("Status code validation passed: %d is in expected range", statusCode)
 
-	// Check required fields if specified
-	if len(template.SuccessCriteria.RequiredFields) > 0 {
-		logger.Debug("Checking %d required fields in JSON response", len(template.SuccessCriteria.RequiredFields))
-		var responseData interface{}
-		if err := json.Unmarshal(resp.Body(), &responseData); err != nil {
-			logger.Info("Response validation failed: API returned invalid JSON")
+	// Check failure criteria before declaring success
+	if !template.FailureCriteria.IsEmpty() {
+		if key, description := matchFailureCriteria(resp, template.FailureCriteria, template.SuccessCriteria.Format()); key != "" {
+			logger.Info("Failure criteria matched: %s", description)
+			errorMsg := fmt.Sprintf(constants.FailureCriteriaMatched, description)
+			if customMsg, ok := template.FailureCriteria.ErrorMessages[key]; ok {
+				errorMsg = customMsg
+			}
 			return &models.ValidationResult{
-				Valid: false,
-				Error: constants.InvalidJSONResponse,
+				Valid:          false,
+				Status:         constants.StatusInvalid,
+				Outcome:        constants.OutcomeFailureCriteria,
+				Error:          errorMsg,
+				MatchedFailure: description,
+			}, nil
+		}
+		logger.Debug("Failure criteria not matched")
+	}
+
+	// Check required headers and body text, which work for any response format
+	if outcome, errorMsg := checkResponseContent(resp, template.SuccessCriteria); outcome != "" {
+		logger.Info("Response content validation failed: %s", errorMsg)
+		return &models.ValidationResult{
+			Valid:   false,
+			Status:  constants.StatusInvalid,
+			Outcome: outcome,
+			Error:   errorMsg,
+		}, nil
+	}
+
+	// Decode the body when fields are checked or a structured format is declared
+	format := template.SuccessCriteria.Format()
+	explicitFormat := template.SuccessCriteria.ResponseFormat != "" && format != constants.ResponseFormatText
+	if template.SuccessCriteria.HasFieldChecks() || explicitFormat {
+		logger.Debug("Checking %d required fields in %s response", len(template.SuccessCriteria.RequiredFields), format)
+		responseData, err := parseResponseBody(resp.Body(), format)
+		if err != nil {
+			logger.Info("Response validation failed: API returned an invalid %s body", format)
+			return &models.ValidationResult{
+				Valid:   false,
+				Status:  constants.StatusInconclusive,
+				Outcome: constants.OutcomeInvalidResponse,
+				Error:   invalidBodyMessage(format),
 			}, nil
 		}
 
@@ -186,17 +428,69 @@ func (c *Client) checkResponse(
 			if err != nil || value == nil {
 				logger.Info("Required field validation failed: '%s' not found in response", fieldPath)
 				return &models.ValidationResult{
-					Valid: false,
-					Error: fmt.Sprintf(constants.RequiredFieldNotFound, fieldPath),
+					Valid:   false,
+					Status:  constants.StatusInvalid,
+					Outcome: constants.OutcomeMissingField,
+					Error:   fmt.Sprintf(constants.RequiredFieldNotFound, fieldPath),
 				}, nil
 			}
 			logger.Debug("Required field validation passed: '%s' found in response", fieldPath)
 		}
+
+		// Check each field assertion
+		for _, assertion := range template.SuccessCriteria.FieldAssertions {
+			if failure := checkFieldAssertion(assertion, responseData); failure != "" {
+				logger.Info("Field assertion failed: %s", failure)
+				errorMsg := fmt.Sprintf(constants.FieldAssertionFailed, failure)
+				if assertion.Message != "" {
+					errorMsg = assertion.Message
+				}
+				return &models.ValidationResult{
+					Valid:           false,
+					Status:          constants.StatusInvalid,
+					Outcome:         constants.OutcomeAssertionFailed,
+					Error:           errorMsg,
+					FailedAssertion: assertion.String(),
+				}, nil
+			}
+			logger.Debug("Field assertion passed: %s", assertion)
+		}
+	}
+
+	// Check the success expression last, once every declarative check has passed
+	if expression := template.SuccessCriteria.Expression; expression != "" {
+		ok, err := evaluateExpression(ctx, expression, resp, template.SuccessCriteria.Format())
+		if err != nil && ctx.Err() != nil {
+			return requestFailure(ctx, template, nil, err), nil
+		}
+		if err != nil {
+			logger.Info("Success expression could not be evaluated: %v", err)
+			return &models.ValidationResult{
+				Valid:   false,
+				Status:  constants.StatusInvalid,
+				Outcome: constants.OutcomeExpressionFailed,
+				Error:   fmt.Sprintf(constants.ExpressionEvalFailed, err),
+			}, nil
+		}
+		if !ok {
+			logger.Info("Success expression not satisfied: %s", expression)
+			return &models.ValidationResult{
+				Valid:   false,
+				Status:  constants.StatusInvalid,
+				Outcome: constants.OutcomeExpressionFailed,
+				Error:   fmt.Sprintf(constants.ExpressionFailed, expression),
+			}, nil
+		}
+		logger.Debug("Success expression satisfied: %s", expression)
 	}
 
 	logger.Info("Validation successful")
 	return &models.ValidationResult{
-		Valid:   true,
-		Message: constants.SecretValid,
+		Valid:     true,
+		Status:    constants.StatusValid,
+		Outcome:   constants.OutcomeValid,
+		Message:   constants.SecretValid,
+		Extracted: extractValues(resp, template.Extract, template.SuccessCriteria.Format()),
+		Scopes:    reportScopes(resp, template.Scopes),
 	}, nil
 }
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+	"github.com/theinfosecguy/archer/internal/models"
+)
+
+// evaluateExpression evaluates a success expression against the response.
+// It returns whether the expression holds, or an error if it cannot be
+// evaluated, does not produce a boolean, or ctx is done.
+func evaluateExpression(ctx context.Context, expression string, resp *resty.Response, format string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	evaluable, err := models.CompileExpression(expression)
+	if err != nil {
+		return false, err
+	}
+
+	value, err := evaluable(ctx, expressionParameters(resp, format))
+	if err != nil {
+		return false, err
+	}
+	result, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression returned %s, expected bool", jsonType(value))
+	}
+	return result, nil
+}
+
+// expressionParameters exposes the status code, headers and body to expressions.
+// Header names are lower-cased and repeated values joined with ", ". The body
+// is decoded according to the response format, falling back to the raw text
+// when it does not parse.
+func expressionParameters(resp *resty.Response, format string) map[string]any {
+	headers := make(map[string]any, len(resp.Header()))
+	for name, values := range resp.Header() {
+		headers[strings.ToLower(name)] = strings.Join(values, ", ")
+	}
+
+	body, err := parseResponseBody(resp.Body(), format)
+	if err != nil {
+		body = string(resp.Body())
+	}
+
+	return map[string]any{
+		constants.ExpressionStatus:  resp.StatusCode(),
+		constants.ExpressionHeaders: headers,
+		constants.ExpressionBody:    body,
+	}
+}
@@ -0,0 +1,80 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/go-resty/resty/v2"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+	"github.com/theinfosecguy/archer/internal/logger"
+	"github.com/theinfosecguy/archer/internal/models"
+)
+
+// extractValues evaluates the template extract map against a successful response,
+// decoding the body according to the response format. Values that cannot be
+// found are skipped; masked fields are masked before they leave this function.
+func extractValues(resp *resty.Response, extract map[string]models.Extraction, format string) map[string]string {
+	if len(extract) == 0 {
+		return nil
+	}
+
+	responseData, err := parseResponseBody(resp.Body(), format)
+	bodyParsed := err == nil
+
+	extracted := make(map[string]string)
+	for name, extraction := range extract {
+		var value string
+		if extraction.Header != "" {
+			value = resp.Header().Get(extraction.Header)
+		} else if bodyParsed {
+			raw, err := jsonpath.Get(extraction.Path, responseData)
+			if err == nil && raw != nil {
+				value = formatExtractedValue(raw)
+			}
+		}
+
+		if value == "" {
+			logger.Debug("Extract '%s' (%s) not found in response", name, extraction.Source())
+			continue
+		}
+
+		if extraction.Mask {
+			value = maskExtractedValue(value)
+		}
+		extracted[name] = value
+	}
+
+	return extracted
+}
+
+// formatExtractedValue converts a decoded JSON value to its display string
+func formatExtractedValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
+
+// maskExtractedValue hides all but the first and last few characters of a value.
+// It counts runes, so multi-byte characters are never split.
+func maskExtractedValue(value string) string {
+	keep := constants.ExtractedMaskKeepChars
+	runes := []rune(value)
+	if len(runes) <= keep*2 {
+		return constants.ExtractedMask
+	}
+	return string(runes[:keep]) + constants.ExtractedMask + string(runes[len(runes)-keep:])
+}
@@ -0,0 +1,51 @@
+package http
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/go-resty/resty/v2"
+
+	"github.com/theinfosecguy/archer/internal/models"
+)
+
+// matchFailureCriteria checks a response against the template failure criteria.
+// It returns the matched criterion (used as the error message key) and a
+// description of the match, or empty strings if nothing matched.
+func matchFailureCriteria(resp *resty.Response, criteria models.FailureCriteria, format string) (string, string) {
+	for _, header := range criteria.HeadersPresent {
+		if resp.Header().Get(header) != "" {
+			return header, fmt.Sprintf("header '%s' present", header)
+		}
+	}
+
+	body := string(resp.Body())
+
+	for _, substring := range criteria.BodyContains {
+		if strings.Contains(body, substring) {
+			return substring, fmt.Sprintf("body contains '%s'", substring)
+		}
+	}
+
+	for _, pattern := range criteria.BodyRegex {
+		re, err := regexp.Compile(pattern)
+		if err == nil && re.MatchString(body) {
+			return pattern, fmt.Sprintf("body matches /%s/", pattern)
+		}
+	}
+
+	if len(criteria.FieldsPresent) > 0 {
+		if responseData, err := parseResponseBody(resp.Body(), format); err == nil {
+			for _, fieldPath := range criteria.FieldsPresent {
+				value, err := jsonpath.Get(fieldPath, responseData)
+				if err == nil && value != nil {
+					return fieldPath, fmt.Sprintf("field '%s' present", fieldPath)
+				}
+			}
+		}
+	}
+
+	return "", ""
+}
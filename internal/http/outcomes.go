@@ -0,0 +1,93 @@
+package http
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/go-resty/resty/v2"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+	"github.com/theinfosecguy/archer/internal/models"
+)
+
+// matchOutcome returns the first outcome whose conditions all match the response.
+// Field conditions look up the body decoded according to the response format.
+func matchOutcome(resp *resty.Response, outcomes []models.Outcome, format string) *models.Outcome {
+	if len(outcomes) == 0 {
+		return nil
+	}
+
+	body := string(resp.Body())
+
+	// Parse the body lazily, only if an outcome needs a JSONPath lookup
+	var responseData any
+	parsed, parseFailed := false, false
+	parseBody := func() bool {
+		if !parsed {
+			parsed = true
+			var err error
+			responseData, err = parseResponseBody(resp.Body(), format)
+			parseFailed = err != nil
+		}
+		return !parseFailed
+	}
+
+	for i := range outcomes {
+		outcome := &outcomes[i]
+
+		if len(outcome.StatusCodes) > 0 && !outcome.StatusCodes.Matches(resp.StatusCode()) {
+			continue
+		}
+
+		if outcome.BodyContains != "" && !strings.Contains(body, outcome.BodyContains) {
+			continue
+		}
+
+		if outcome.BodyRegex != "" {
+			re, err := regexp.Compile(outcome.BodyRegex)
+			if err != nil || !re.MatchString(body) {
+				continue
+			}
+		}
+
+		if outcome.Field != "" {
+			if !parseBody() {
+				continue
+			}
+			value, err := jsonpath.Get(outcome.Field, responseData)
+			if err != nil || value == nil {
+				continue
+			}
+			if outcome.Equals != nil && !valuesEqual(outcome.Equals, value) {
+				continue
+			}
+		}
+
+		return outcome
+	}
+
+	return nil
+}
+
+// outcomeResult builds a validation result from a matched outcome
+func outcomeResult(outcome *models.Outcome) *models.ValidationResult {
+	message := outcome.Message
+	if message == "" {
+		message = fmt.Sprintf(constants.OutcomeMatched, outcome.Name)
+	}
+
+	status := outcome.ResolvedStatus()
+	result := &models.ValidationResult{
+		Valid:   status == constants.StatusValid,
+		Status:  status,
+		Outcome: outcome.Name,
+	}
+	if result.Valid {
+		result.Message = message
+	} else {
+		result.Error = message
+	}
+	return result
+}
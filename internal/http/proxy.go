@@ -0,0 +1,61 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/http/httpproxy"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+	"github.com/theinfosecguy/archer/internal/models"
+)
+
+// proxyFunc selects the proxy for a request, returning nil to connect directly
+type proxyFunc func(*http.Request) (*url.URL, error)
+
+// ParseProxy validates a proxy URL. A URL without a scheme is taken to be an
+// HTTP proxy. Errors never quote the URL, as it may contain credentials.
+func ParseProxy(raw string) (*url.URL, error) {
+	if !strings.Contains(raw, "://") {
+		raw = constants.DefaultProxyScheme + "://" + raw
+	}
+	proxy, err := url.Parse(raw)
+	if err != nil || proxy.Host == "" {
+		return nil, fmt.Errorf(constants.ProxyInvalid)
+	}
+	if !constants.ProxySchemes[proxy.Scheme] {
+		return nil, fmt.Errorf(constants.ProxySchemeInvalid, proxy.Scheme)
+	}
+	return proxy, nil
+}
+
+// fixedProxy routes every request through proxy, except for hosts excluded by
+// NO_PROXY
+func fixedProxy(proxy *url.URL) proxyFunc {
+	config := httpproxy.FromEnvironment()
+	config.HTTPProxy = proxy.String()
+	config.HTTPSProxy = proxy.String()
+	resolve := config.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return resolve(req.URL)
+	}
+}
+
+// route describes how requests to requestURL travel: "direct", or the proxy
+// URL with its password redacted
+func (c *Client) route(template *models.SecretTemplate, requestURL string) string {
+	if template.NoProxy || c.proxy == nil {
+		return constants.RouteDirect
+	}
+	target, err := url.Parse(requestURL)
+	if err != nil {
+		return constants.RouteDirect
+	}
+	proxy, err := c.proxy(&http.Request{URL: target})
+	if err != nil || proxy == nil {
+		return constants.RouteDirect
+	}
+	return proxy.Redacted()
+}
@@ -0,0 +1,126 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+	"github.com/theinfosecguy/archer/internal/models"
+)
+
+// parseResponseBody decodes a response body according to the template's
+// response_format. JSON bodies decode as usual, form bodies decode to an object
+// keyed by field name (repeated fields become arrays), and text and XML bodies
+// are returned as strings once XML has been checked for well-formedness.
+func parseResponseBody(body []byte, format string) (any, error) {
+	switch format {
+	case constants.ResponseFormatForm:
+		values, err := url.ParseQuery(strings.TrimSpace(string(body)))
+		if err != nil {
+			return nil, err
+		}
+		fields := make(map[string]any, len(values))
+		for key, list := range values {
+			if len(list) == 1 {
+				fields[key] = list[0]
+				continue
+			}
+			items := make([]any, len(list))
+			for i, item := range list {
+				items[i] = item
+			}
+			fields[key] = items
+		}
+		return fields, nil
+	case constants.ResponseFormatXML:
+		if err := checkXML(body); err != nil {
+			return nil, err
+		}
+		return string(body), nil
+	case constants.ResponseFormatText:
+		return string(body), nil
+	default:
+		var data any
+		if err := json.Unmarshal(body, &data); err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+}
+
+// checkXML reports whether body is a well-formed XML document
+func checkXML(body []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	elements := 0
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if _, ok := token.(xml.StartElement); ok {
+			elements++
+		}
+	}
+	if elements == 0 {
+		return errors.New("no root element")
+	}
+	return nil
+}
+
+// invalidBodyMessage describes a body that does not parse as the expected format
+func invalidBodyMessage(format string) string {
+	switch format {
+	case constants.ResponseFormatJSON:
+		return constants.InvalidJSONResponse
+	case constants.ResponseFormatForm:
+		return fmt.Sprintf(constants.InvalidResponseBody, "form-encoded")
+	default:
+		return fmt.Sprintf(constants.InvalidResponseBody, strings.ToUpper(format))
+	}
+}
+
+// checkResponseContent evaluates the header and body text criteria, which
+// apply to any response format. It returns the outcome name and error message
+// of the first failed check, or empty strings if every check passed.
+func checkResponseContent(resp *resty.Response, criteria models.SuccessCriteria) (string, string) {
+	for _, header := range criteria.RequiredHeaders {
+		if resp.Header().Get(header) == "" {
+			return constants.OutcomeMissingHeader, fmt.Sprintf(constants.RequiredHeaderNotFound, header)
+		}
+	}
+
+	body := string(resp.Body())
+
+	for _, substring := range criteria.BodyContains {
+		if !strings.Contains(body, substring) {
+			return constants.OutcomeBodyMismatch, fmt.Sprintf(constants.BodyMissingSubstring, substring)
+		}
+	}
+
+	for _, substring := range criteria.BodyNotContains {
+		if strings.Contains(body, substring) {
+			return constants.OutcomeBodyMismatch, fmt.Sprintf(constants.BodyHasSubstring, substring)
+		}
+	}
+
+	for _, pattern := range criteria.BodyRegex {
+		re, err := regexp.Compile(pattern)
+		if err != nil || !re.MatchString(body) {
+			return constants.OutcomeBodyMismatch, fmt.Sprintf(constants.BodyRegexNotMatched, pattern)
+		}
+	}
+
+	return "", ""
+}
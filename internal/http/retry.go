@@ -0,0 +1,156 @@
+package http
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+	"github.com/theinfosecguy/archer/internal/logger"
+	"github.com/theinfosecguy/archer/internal/models"
+	"github.com/theinfosecguy/archer/internal/variables"
+)
+
+// execute sends the request, retrying network errors and retry_on statuses up
+// to max_retries times. It returns the last response or error together with a
+// record of every attempt.
+func (c *Client) execute(
+	ctx context.Context,
+	client *resty.Client,
+	template *models.SecretTemplate,
+	requestURL string,
+	vars map[string]string,
+	configure func(*resty.Request),
+) (*resty.Response, []models.RequestAttempt, error) {
+	handling := &template.ErrorHandling
+	retryStatuses := handling.RetryStatuses()
+	maxAttempts := handling.MaxRetries + 1
+	attempts := make([]models.RequestAttempt, 0, maxAttempts)
+
+	route := c.route(template, requestURL)
+	logger.Debug("Route: %s", route)
+
+	for n := 1; ; n++ {
+		start := time.Now()
+		resp, err := attempt(ctx, client, template, requestURL, configure)
+		record := models.RequestAttempt{Attempt: n, Route: route, DurationMs: time.Since(start).Milliseconds()}
+		if err != nil {
+			record.Error = variables.MaskSecretValues(err.Error(), vars, template.IsSecretVariable)
+		} else {
+			record.StatusCode = resp.StatusCode()
+		}
+		attempts = append(attempts, record)
+
+		if n >= maxAttempts || ctx.Err() != nil || (err == nil && !retryStatuses.Matches(resp.StatusCode())) {
+			return resp, attempts, err
+		}
+
+		delay, ok := retryDelay(handling, n, resp, time.Now())
+		if !ok {
+			logger.Info("Attempt %d/%d: provider asked to wait longer than %s; not retrying", n, maxAttempts, handling.RetryDelayCap())
+			return resp, attempts, err
+		}
+		delayMs := delay.Milliseconds()
+		attempts[len(attempts)-1].RetryDelayMs = &delayMs
+
+		if err != nil {
+			logger.Info("Attempt %d/%d failed: %s; retrying in %s", n, maxAttempts, record.Error, delay)
+		} else {
+			logger.Info("Attempt %d/%d returned status %d; retrying in %s", n, maxAttempts, resp.StatusCode(), delay)
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, attempts, err
+		}
+	}
+}
+
+// attempt sends the request once, bounded by the template timeout
+func attempt(
+	ctx context.Context,
+	client *resty.Client,
+	template *models.SecretTemplate,
+	requestURL string,
+	configure func(*resty.Request),
+) (*resty.Response, error) {
+	if template.Request.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(template.Request.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	req := client.R().SetContext(ctx)
+	configure(req)
+	return req.Execute(template.Method, requestURL)
+}
+
+// retryDelay returns how long to wait before retry number n (1-based). A wait
+// requested by the provider on 429 and 503 responses takes precedence over the
+// backoff strategy; if it exceeds max_retry_delay, ok is false and the request
+// is not retried.
+func retryDelay(handling *models.ErrorHandling, n int, resp *resty.Response, now time.Time) (time.Duration, bool) {
+	limit := handling.RetryDelayCap()
+	if resp != nil && slices.Contains(constants.RetryAfterStatuses, resp.StatusCode()) {
+		if wait, found := serverRetryDelay(resp.Header(), now); found {
+			logger.Debug("Provider requested a %s wait", wait)
+			return wait, wait <= limit
+		}
+	}
+	return backoffDelay(handling, n, limit), true
+}
+
+// backoffDelay computes the wait before retry number n from retry_delay and
+// the backoff strategy, capped at limit
+func backoffDelay(handling *models.ErrorHandling, n int, limit time.Duration) time.Duration {
+	delay := handling.RetryDelay.Duration()
+	strategy := handling.BackoffStrategy()
+	if strategy == constants.BackoffExponential || strategy == constants.BackoffJittered {
+		for i := 1; i < n && delay < limit; i++ {
+			delay *= 2
+		}
+	}
+	delay = min(delay, limit)
+	if strategy == constants.BackoffJittered && delay > 0 {
+		half := delay / 2
+		delay = half + rand.N(delay-half+1)
+	}
+	return delay
+}
+
+// serverRetryDelay reads the wait requested by Retry-After (seconds or an HTTP
+// date) or X-RateLimit-Reset (a Unix timestamp or seconds)
+func serverRetryDelay(header http.Header, now time.Time) (time.Duration, bool) {
+	if value := header.Get(constants.HeaderRetryAfter); value != "" {
+		if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if at, err := http.ParseTime(value); err == nil {
+			return max(at.Sub(now), 0), true
+		}
+	}
+	if value := header.Get(constants.HeaderRateLimitReset); value != "" {
+		if reset, err := strconv.ParseInt(value, 10, 64); err == nil && reset >= 0 {
+			if reset >= constants.RateLimitResetEpoch {
+				return max(time.Unix(reset, 0).Sub(now), 0), true
+			}
+			return time.Duration(reset) * time.Second, true
+		}
+	}
+	return 0, false
+}
+
+// sleepContext waits for d, returning early with the context error if ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
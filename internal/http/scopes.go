@@ -0,0 +1,49 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+	"github.com/theinfosecguy/archer/internal/logger"
+	"github.com/theinfosecguy/archer/internal/models"
+)
+
+// reportScopes reads the granted scopes from the configured response header and
+// computes the privilege level as the highest risk among them. It returns nil
+// when the template has no scope config or the header is absent (for example,
+// fine-grained GitHub tokens do not send X-OAuth-Scopes).
+func reportScopes(resp *resty.Response, config *models.ScopeConfig) *models.ScopeReport {
+	if config == nil {
+		return nil
+	}
+
+	values := resp.Header().Values(config.Header)
+	if len(values) == 0 {
+		logger.Debug("Scope header '%s' not present in response", config.Header)
+		return nil
+	}
+
+	report := &models.ScopeReport{
+		Scopes:         []models.ScopeGrant{},
+		PrivilegeLevel: constants.RiskLevelNone,
+	}
+	for _, value := range values {
+		for _, scope := range strings.Split(value, config.ListSeparator()) {
+			scope = strings.TrimSpace(scope)
+			if scope == "" {
+				continue
+			}
+
+			risk := config.RiskFor(scope)
+			report.Scopes = append(report.Scopes, models.ScopeGrant{Name: scope, Risk: risk})
+			if constants.RiskLevelRanks[risk] > constants.RiskLevelRanks[report.PrivilegeLevel] {
+				report.PrivilegeLevel = risk
+			}
+		}
+	}
+
+	logger.Debug("Granted scopes: %d, privilege level: %s", len(report.Scopes), report.PrivilegeLevel)
+	return report
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/go-resty/resty/v2"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+	"github.com/theinfosecguy/archer/internal/models"
+)
+
+// newTLSConfig builds the TLS settings for a transport, loading CA bundles
+// and the client certificate from disk. An empty config returns nil, keeping
+// Go's defaults.
+func newTLSConfig(options models.TLSConfig) (*tls.Config, error) {
+	if options.IsEmpty() {
+		return nil, nil
+	}
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		MinVersion:         constants.TLSVersions[options.MinVersion],
+		InsecureSkipVerify: options.InsecureSkipVerify,
+	}
+
+	if len(options.CAFiles) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		for _, file := range options.CAFiles {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+			if !pool.AppendCertsFromPEM(data) {
+				return nil, fmt.Errorf(constants.TLSCAFileInvalid, file)
+			}
+		}
+		config.RootCAs = pool
+	}
+
+	if options.CertFile != "" {
+		keyFile := options.KeyFile
+		if keyFile == "" {
+			keyFile = options.CertFile
+		}
+		certificate, err := tls.LoadX509KeyPair(options.CertFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf(constants.TLSClientCertInvalid, err)
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+
+	if len(options.Pins) > 0 {
+		pins := make([][]byte, len(options.Pins))
+		for i, pin := range options.Pins {
+			pins[i], _ = models.ParsePin(pin)
+		}
+		config.VerifyConnection = verifyPins(pins)
+	}
+
+	return config, nil
+}
+
+// verifyPins accepts a connection if the SHA-256 hash of the public key of a
+// certificate in a verified chain matches a pin. It runs after, not instead
+// of, normal chain verification. Extra certificates the server sends are never
+// considered, as anyone can present a copy of a pinned public certificate; when
+// verification is skipped, only the leaf can match.
+func verifyPins(pins [][]byte) func(tls.ConnectionState) error {
+	return func(state tls.ConnectionState) error {
+		var candidates []*x509.Certificate
+		for _, chain := range state.VerifiedChains {
+			candidates = append(candidates, chain...)
+		}
+		if len(state.VerifiedChains) == 0 && len(state.PeerCertificates) > 0 {
+			candidates = state.PeerCertificates[:1]
+		}
+
+		for _, certificate := range candidates {
+			hash := sha256.Sum256(certificate.RawSubjectPublicKeyInfo)
+			for _, pin := range pins {
+				if bytes.Equal(hash[:], pin) {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf(constants.TLSPinMismatch, state.ServerName)
+	}
+}
+
+// clientFor returns the resty client for a template. Templates with their own
+// TLS settings get a client built for the merged settings, cached so later
+// requests reuse its connections.
+func (c *Client) clientFor(template *models.SecretTemplate) (*resty.Client, error) {
+	if template.TLS.IsEmpty() {
+		if template.NoProxy {
+			return c.directClient, nil
+		}
+		return c.restyClient, nil
+	}
+
+	options := c.tls.Merge(template.TLS)
+	key := fmt.Sprintf("%t %+v", template.NoProxy, options)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if client, ok := c.tlsClients[key]; ok {
+		return client, nil
+	}
+
+	tlsConfig, err := newTLSConfig(options)
+	if err != nil {
+		return nil, err
+	}
+	proxy := c.proxy
+	if template.NoProxy {
+		proxy = nil
+	}
+	client := resty.NewWithClient(&http.Client{Transport: newTransport(proxy, tlsConfig)})
+	c.tlsClients[key] = client
+	return client, nil
+}
@@ -0,0 +1,298 @@
+// Package inspect identifies secrets and decodes what their format reveals,
+// without sending them anywhere.
+package inspect
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+	"github.com/theinfosecguy/archer/internal/models"
+)
+
+// detector inspects a secret and returns nil when it does not recognise the format
+type detector func(secret string, now time.Time) *models.TokenReport
+
+var detectors = []detector{
+	inspectGitHub,
+	inspectAWS,
+	inspectSlack,
+	inspectStripe,
+	inspectJWT,
+}
+
+// Inspect analyses a secret offline and reports its provider, type and decoded fields
+func Inspect(secret string) *models.TokenReport {
+	return inspectAt(strings.TrimSpace(secret), time.Now().UTC())
+}
+
+func inspectAt(secret string, now time.Time) *models.TokenReport {
+	for _, detect := range detectors {
+		if report := detect(secret, now); report != nil {
+			report.Detected = true
+			return report
+		}
+	}
+	return &models.TokenReport{}
+}
+
+// tokenPrefix describes one token type identified by its prefix. Which template
+// validates it is decided by the templates' secret_format, not here.
+type tokenPrefix struct {
+	prefix string
+	label  string
+}
+
+// matchPrefix returns the longest prefix in the table that the secret starts with
+func matchPrefix(secret string, prefixes []tokenPrefix) (tokenPrefix, bool) {
+	var matched tokenPrefix
+	for _, p := range prefixes {
+		if strings.HasPrefix(secret, p.prefix) && len(p.prefix) > len(matched.prefix) {
+			matched = p
+		}
+	}
+	return matched, matched.prefix != ""
+}
+
+var githubPrefixes = []tokenPrefix{
+	{"ghp_", "personal access token (classic)"},
+	{"gho_", "OAuth access token"},
+	{"ghu_", "GitHub App user-to-server token"},
+	{"ghs_", "GitHub App installation token"},
+	{"ghr_", "GitHub App refresh token"},
+	{constants.GitHubFineGrainedPrefix, "fine-grained personal access token"},
+}
+
+var base62Pattern = regexp.MustCompile(`^[0-9A-Za-z]+$`)
+
+// inspectGitHub recognises GitHub tokens and verifies the CRC32 checksum in the
+// last six characters of prefixed tokens
+func inspectGitHub(secret string, now time.Time) *models.TokenReport {
+	p, ok := matchPrefix(secret, githubPrefixes)
+	if !ok {
+		return nil
+	}
+	report := &models.TokenReport{Provider: constants.ProviderGitHub, Type: p.label}
+
+	body := secret[len(p.prefix):]
+	if p.prefix == constants.GitHubFineGrainedPrefix {
+		// Fine-grained tokens do not use a published checksum
+		if id, _, found := strings.Cut(body, "_"); found {
+			report.AddDetail("Token ID", id)
+		}
+		return report
+	}
+
+	if len(body) != constants.GitHubTokenRandomLength+constants.GitHubTokenChecksumLength || !base62Pattern.MatchString(body) {
+		checksumValid := false
+		report.ChecksumValid = &checksumValid
+		report.AddDetail("Checksum", "malformed token body")
+		return report
+	}
+
+	random := body[:constants.GitHubTokenRandomLength]
+	checksum := body[constants.GitHubTokenRandomLength:]
+	checksumValid := encodeBase62(uint64(crc32.ChecksumIEEE([]byte(random))), constants.GitHubTokenChecksumLength) == checksum
+	report.ChecksumValid = &checksumValid
+	return report
+}
+
+// encodeBase62 encodes n in base62, left-padded with zeros to width
+func encodeBase62(n uint64, width int) string {
+	encoded := make([]byte, 0, width)
+	for n > 0 {
+		encoded = append(encoded, constants.Base62Alphabet[n%62])
+		n /= 62
+	}
+	for len(encoded) < width {
+		encoded = append(encoded, constants.Base62Alphabet[0])
+	}
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+	return string(encoded)
+}
+
+var awsPrefixes = []tokenPrefix{
+	{"AKIA", "access key ID (long-term)"},
+	{"ASIA", "access key ID (temporary, STS)"},
+	{"ABIA", "AWS STS service bearer token"},
+	{"ACCA", "context-specific credential"},
+	{"AIDA", "IAM user unique ID"},
+	{"AROA", "IAM role unique ID"},
+	{"AGPA", "IAM group unique ID"},
+	{"ANPA", "managed policy unique ID"},
+	{"ANVA", "managed policy version unique ID"},
+	{"AIPA", "EC2 instance profile unique ID"},
+	{"APKA", "public key ID"},
+	{"ASCA", "certificate ID"},
+}
+
+var awsKeyIDPattern = regexp.MustCompile(`^[A-Z0-9]{4}[A-Z2-7]{16}$`)
+
+// inspectAWS recognises AWS key and unique IDs and decodes the account ID
+// encoded in their base32 body
+func inspectAWS(secret string, now time.Time) *models.TokenReport {
+	if len(secret) != constants.AWSAccessKeyIDLength || !awsKeyIDPattern.MatchString(secret) {
+		return nil
+	}
+	p, ok := matchPrefix(secret, awsPrefixes)
+	if !ok {
+		return nil
+	}
+	report := &models.TokenReport{Provider: constants.ProviderAWS, Type: p.label}
+
+	decoded, err := base32.StdEncoding.DecodeString(secret[4:])
+	if err == nil && len(decoded) >= 6 {
+		var value uint64
+		for _, b := range decoded[:6] {
+			value = value<<8 | uint64(b)
+		}
+		accountID := (value & constants.AWSAccountIDMask) >> constants.AWSAccountIDShift
+		report.AddDetail("Account ID", fmt.Sprintf("%012d", accountID))
+	}
+	return report
+}
+
+var slackPrefixes = []tokenPrefix{
+	{"xoxb-", "bot token"},
+	{"xoxp-", "user token"},
+	{"xoxa-", "workspace app token"},
+	{"xapp-", "app-level token"},
+	{"xoxe.xoxb-", "rotating bot token"},
+	{"xoxe.xoxp-", "rotating user or configuration access token"},
+	{"xoxe-", "configuration refresh token"},
+	{"xoxr-", "refresh token"},
+	{"xoxs-", "session token"},
+	{"xoxc-", "browser session token"},
+}
+
+// inspectSlack classifies Slack tokens by prefix
+func inspectSlack(secret string, now time.Time) *models.TokenReport {
+	p, ok := matchPrefix(secret, slackPrefixes)
+	if !ok {
+		return nil
+	}
+	report := &models.TokenReport{Provider: constants.ProviderSlack, Type: p.label}
+
+	// Bot and user tokens carry the numeric workspace ID as their first field
+	fields := strings.Split(secret[len(p.prefix):], "-")
+	if len(fields) > 2 && (p.prefix == "xoxb-" || p.prefix == "xoxp-") {
+		if _, err := strconv.ParseUint(fields[0], 10, 64); err == nil {
+			report.AddDetail("Workspace ID", fields[0])
+		}
+	}
+	return report
+}
+
+var stripePrefixes = []tokenPrefix{
+	{"sk_live_", "secret key"},
+	{"sk_test_", "secret key"},
+	{"rk_live_", "restricted key"},
+	{"rk_test_", "restricted key"},
+	{"pk_live_", "publishable key (not secret)"},
+	{"pk_test_", "publishable key (not secret)"},
+	{"whsec_", "webhook signing secret"},
+}
+
+// inspectStripe distinguishes Stripe live, test and restricted keys
+func inspectStripe(secret string, now time.Time) *models.TokenReport {
+	p, ok := matchPrefix(secret, stripePrefixes)
+	if !ok {
+		return nil
+	}
+	report := &models.TokenReport{Provider: constants.ProviderStripe, Type: p.label}
+
+	switch {
+	case strings.HasSuffix(p.prefix, "_live_"):
+		report.AddDetail("Environment", "live")
+	case strings.HasSuffix(p.prefix, "_test_"):
+		report.AddDetail("Environment", "test")
+	}
+	return report
+}
+
+var jwtPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*$`)
+
+// jwtTimeClaims are the registered claims holding NumericDate values
+var jwtTimeClaims = map[string]bool{"exp": true, "iat": true, "nbf": true}
+
+// inspectJWT decodes the header and claims of a JSON Web Token. The signature is not verified.
+func inspectJWT(secret string, now time.Time) *models.TokenReport {
+	if !jwtPattern.MatchString(secret) {
+		return nil
+	}
+	parts := strings.Split(secret, ".")
+
+	var header map[string]any
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return nil
+	}
+	if _, ok := header["alg"]; !ok {
+		return nil
+	}
+	var claims map[string]any
+	if err := decodeJWTSegment(parts[1], &claims); err != nil {
+		return nil
+	}
+
+	report := &models.TokenReport{Provider: constants.ProviderJWT, Type: "JSON Web Token"}
+	for _, name := range []string{"alg", "typ"} {
+		report.AddDetail("header."+name, formatClaim(name, header[name]))
+	}
+
+	names := make([]string, 0, len(claims))
+	for name := range claims {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		report.AddDetail(name, formatClaim(name, claims[name]))
+	}
+
+	if exp, ok := claims["exp"].(float64); ok {
+		expiresAt := time.Unix(int64(exp), 0).UTC()
+		expired := !now.Before(expiresAt)
+		report.ExpiresAt = &expiresAt
+		report.Expired = &expired
+	}
+	return report
+}
+
+// decodeJWTSegment decodes one base64url JWT segment into v
+func decodeJWTSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(segment, "="))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// formatClaim renders a decoded claim for display; time claims are shown as RFC 3339
+func formatClaim(name string, value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		if jwtTimeClaims[name] {
+			return time.Unix(int64(v), 0).UTC().Format(time.RFC3339)
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(encoded)
+	}
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+)
+
+// FieldAssertion represents a value assertion on a JSON response field.
+// Every check that is set must pass for the assertion to succeed.
+type FieldAssertion struct {
+	Path        string   `yaml:"path" json:"path"`                                 // JSONPath of the field to check
+	Equals      any      `yaml:"equals,omitempty" json:"equals,omitempty"`         // Field must equal this value
+	NotEquals   any      `yaml:"not_equals,omitempty" json:"not_equals,omitempty"` // Field must not equal this value
+	Matches     string   `yaml:"matches,omitempty" json:"matches,omitempty"`       // Field (as string) must match this regex
+	Type        string   `yaml:"type,omitempty" json:"type,omitempty"`             // Field must be of this JSON type
+	Length      *int     `yaml:"length,omitempty" json:"length,omitempty"`         // Array or string length must equal this value
+	MinLength   *int     `yaml:"min_length,omitempty" json:"min_length,omitempty"` // Array or string length must be at least this value
+	MaxLength   *int     `yaml:"max_length,omitempty" json:"max_length,omitempty"` // Array or string length must be at most this value
+	GreaterThan *float64 `yaml:"gt,omitempty" json:"gt,omitempty"`                 // Numeric field must be greater than this value
+	GreaterOrEq *float64 `yaml:"gte,omitempty" json:"gte,omitempty"`               // Numeric field must be greater than or equal to this value
+	LessThan    *float64 `yaml:"lt,omitempty" json:"lt,omitempty"`                 // Numeric field must be less than this value
+	LessOrEq    *float64 `yaml:"lte,omitempty" json:"lte,omitempty"`               // Numeric field must be less than or equal to this value
+	Message     string   `yaml:"message,omitempty" json:"message,omitempty"`       // Custom error message when the assertion fails
+}
+
+// Validate validates the field assertion definition
+func (a *FieldAssertion) Validate() error {
+	if strings.TrimSpace(a.Path) == "" {
+		return fmt.Errorf(constants.AssertionPathRequired)
+	}
+
+	if a.Type != "" && !constants.AssertionTypes[a.Type] {
+		return fmt.Errorf(constants.AssertionInvalidType, a.Path, a.Type)
+	}
+
+	if a.Matches != "" {
+		if _, err := regexp.Compile(a.Matches); err != nil {
+			return fmt.Errorf(constants.AssertionInvalidRegex, a.Path, err)
+		}
+	}
+
+	if a.Equals == nil && a.NotEquals == nil && a.Matches == "" && a.Type == "" &&
+		a.Length == nil && a.MinLength == nil && a.MaxLength == nil &&
+		a.GreaterThan == nil && a.GreaterOrEq == nil && a.LessThan == nil && a.LessOrEq == nil {
+		return fmt.Errorf(constants.AssertionNoChecks, a.Path)
+	}
+
+	return nil
+}
+
+// String returns a human-readable description of the assertion
+func (a FieldAssertion) String() string {
+	checks := make([]string, 0)
+	if a.Equals != nil {
+		checks = append(checks, fmt.Sprintf("equals %v", a.Equals))
+	}
+	if a.NotEquals != nil {
+		checks = append(checks, fmt.Sprintf("not_equals %v", a.NotEquals))
+	}
+	if a.Matches != "" {
+		checks = append(checks, fmt.Sprintf("matches /%s/", a.Matches))
+	}
+	if a.Type != "" {
+		checks = append(checks, fmt.Sprintf("type %s", a.Type))
+	}
+	if a.Length != nil {
+		checks = append(checks, fmt.Sprintf("length %d", *a.Length))
+	}
+	if a.MinLength != nil {
+		checks = append(checks, fmt.Sprintf("min_length %d", *a.MinLength))
+	}
+	if a.MaxLength != nil {
+		checks = append(checks, fmt.Sprintf("max_length %d", *a.MaxLength))
+	}
+	if a.GreaterThan != nil {
+		checks = append(checks, fmt.Sprintf("gt %v", *a.GreaterThan))
+	}
+	if a.GreaterOrEq != nil {
+		checks = append(checks, fmt.Sprintf("gte %v", *a.GreaterOrEq))
+	}
+	if a.LessThan != nil {
+		checks = append(checks, fmt.Sprintf("lt %v", *a.LessThan))
+	}
+	if a.LessOrEq != nil {
+		checks = append(checks, fmt.Sprintf("lte %v", *a.LessOrEq))
+	}
+	return fmt.Sprintf("%s %s", a.Path, strings.Join(checks, ", "))
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+)
+
+// Capability is a named read-only probe request used by `archer validate --probe`
+// to find out what a valid secret is allowed to do. Probes reuse the template
+// request headers unless they override them.
+type Capability struct {
+	Name          string        `yaml:"name" json:"name"`
+	Description   string        `yaml:"description,omitempty" json:"description,omitempty"`
+	APIURL        string        `yaml:"api_url" json:"api_url"`
+	Method        string        `yaml:"method,omitempty" json:"method,omitempty"`
+	Request       RequestConfig `yaml:"request,omitempty" json:"request,omitempty"`
+	AllowedStatus []int         `yaml:"allowed_status,omitempty" json:"allowed_status,omitempty"` // Defaults to [200]
+	DeniedStatus  []int         `yaml:"denied_status,omitempty" json:"denied_status,omitempty"`   // Defaults to [401, 403, 404]
+}
+
+// SetDefaults sets default values for the capability, inheriting the template timeout
+func (c *Capability) SetDefaults(parent *SecretTemplate) {
+	if c.Method == "" {
+		c.Method = constants.MethodGet
+	}
+	c.Method = strings.ToUpper(c.Method)
+	if c.Request.Timeout == 0 {
+		c.Request.Timeout = parent.Request.Timeout
+	}
+	if len(c.AllowedStatus) == 0 {
+		c.AllowedStatus = slices.Clone(constants.DefaultCapabilityAllowedStatus)
+	}
+	if len(c.DeniedStatus) == 0 {
+		c.DeniedStatus = slices.Clone(constants.DefaultCapabilityDeniedStatus)
+	}
+}
+
+// Validate validates the capability definition
+func (c *Capability) Validate() error {
+	if !constants.SnakeCaseNamePattern.MatchString(c.Name) {
+		return fmt.Errorf(constants.CapabilityInvalidName, c.Name)
+	}
+	if c.APIURL == "" {
+		return fmt.Errorf(constants.CapabilityURLRequired, c.Name)
+	}
+	if !constants.ReadOnlyMethods[c.Method] {
+		return fmt.Errorf(constants.CapabilityMethodNotAllowed, c.Name, c.Method)
+	}
+	return c.Request.Validate()
+}
+
+// Template returns a template describing the probe request. Headers are merged
+// over the parent template headers so probes can reuse its authentication, and
+// probes are retried as the parent request is.
+func (c *Capability) Template(parent *SecretTemplate) *SecretTemplate {
+	request := c.Request
+	request.Headers = make(map[string]string, len(parent.Request.Headers)+len(c.Request.Headers))
+	maps.Copy(request.Headers, parent.Request.Headers)
+	maps.Copy(request.Headers, c.Request.Headers)
+
+	return &SecretTemplate{
+		Name:              parent.Name,
+		Mode:              parent.Mode,
+		OptionalVariables: parent.OptionalVariables,
+		Variables:         parent.Variables,
+		NoProxy:           parent.NoProxy,
+		TLS:               parent.TLS,
+		APIURL:            c.APIURL,
+		Method:            c.Method,
+		Request:           request,
+		ErrorHandling:     parent.ErrorHandling,
+	}
+}
+
+// Classify maps a probe response status code to allowed, denied or error
+func (c *Capability) Classify(statusCode int) string {
+	if slices.Contains(c.AllowedStatus, statusCode) {
+		return constants.CapabilityAllowed
+	}
+	if slices.Contains(c.DeniedStatus, statusCode) {
+		return constants.CapabilityDenied
+	}
+	return constants.CapabilityError
+}
+
+// CapabilityResult is one row of the capability matrix
+type CapabilityResult struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Result      string `json:"result"` // allowed, denied or error
+	StatusCode  *int   `json:"status_code,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/PaesslerAG/gval"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+)
+
+// expressionLanguage is the gval full language extended with len()
+var expressionLanguage = gval.Full(gval.Function(constants.ExpressionLen, expressionLen))
+
+// CompileExpression parses a success expression so syntax errors surface when
+// the template is loaded rather than during validation
+func CompileExpression(expression string) (gval.Evaluable, error) {
+	return expressionLanguage.NewEvaluable(expression)
+}
+
+// expressionLen returns the length of a string, array or object
+func expressionLen(value any) (float64, error) {
+	switch v := value.(type) {
+	case string:
+		return float64(utf8.RuneCountInString(v)), nil
+	case []any:
+		return float64(len(v)), nil
+	case map[string]any:
+		return float64(len(v)), nil
+	case nil:
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("len() of %T is not defined", value)
+	}
+}
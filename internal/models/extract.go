@@ -0,0 +1,58 @@
+package models
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+)
+
+// Extraction describes a value pulled from a successful validation response,
+// such as the account login or ID the secret belongs to.
+// In YAML a plain string is shorthand for a JSONPath.
+type Extraction struct {
+	Path   string `yaml:"path,omitempty" json:"path,omitempty"`     // JSONPath into the response body
+	Header string `yaml:"header,omitempty" json:"header,omitempty"` // Response header name
+	Mask   bool   `yaml:"mask,omitempty" json:"mask,omitempty"`     // Mask the value in output (for PII such as emails)
+}
+
+// UnmarshalYAML supports both the shorthand string form and the full mapping form
+func (e *Extraction) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		e.Path = value.Value
+		return nil
+	}
+
+	type rawExtraction Extraction
+	var raw rawExtraction
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+	*e = Extraction(raw)
+	return nil
+}
+
+// Validate validates the extraction definition
+func (e *Extraction) Validate(name string) error {
+	if !constants.SnakeCaseNamePattern.MatchString(name) {
+		return fmt.Errorf(constants.ExtractInvalidName, name)
+	}
+	return e.validateSource(name)
+}
+
+// validateSource checks that exactly one value source is set
+func (e *Extraction) validateSource(name string) error {
+	if (e.Path == "") == (e.Header == "") {
+		return fmt.Errorf(constants.ExtractSourceRequired, name)
+	}
+	return nil
+}
+
+// Source returns a human-readable description of where the value comes from
+func (e Extraction) Source() string {
+	if e.Header != "" {
+		return "header " + e.Header
+	}
+	return e.Path
+}
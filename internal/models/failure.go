@@ -0,0 +1,34 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+)
+
+// FailureCriteria represents conditions that mark a response invalid even when
+// the status code matches the success criteria (e.g. GraphQL "errors" payloads)
+type FailureCriteria struct {
+	FieldsPresent  []string          `yaml:"fields_present,omitempty" json:"fields_present,omitempty"`   // JSONPath fields whose presence indicates failure
+	BodyContains   []string          `yaml:"body_contains,omitempty" json:"body_contains,omitempty"`     // Body substrings that indicate failure
+	BodyRegex      []string          `yaml:"body_regex,omitempty" json:"body_regex,omitempty"`           // Body regex patterns that indicate failure
+	HeadersPresent []string          `yaml:"headers_present,omitempty" json:"headers_present,omitempty"` // Response headers whose presence indicates failure
+	ErrorMessages  map[string]string `yaml:"error_messages,omitempty" json:"error_messages,omitempty"`   // Custom messages keyed by the matched field, substring, pattern or header
+}
+
+// Validate validates the failure criteria
+func (f *FailureCriteria) Validate() error {
+	for _, pattern := range f.BodyRegex {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf(constants.FailureInvalidRegex, pattern, err)
+		}
+	}
+	return nil
+}
+
+// IsEmpty reports whether no failure conditions are configured
+func (f *FailureCriteria) IsEmpty() bool {
+	return len(f.FieldsPresent) == 0 && len(f.BodyContains) == 0 &&
+		len(f.BodyRegex) == 0 && len(f.HeadersPresent) == 0
+}
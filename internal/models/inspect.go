@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// TokenReport describes what can be learned about a secret without network access
+type TokenReport struct {
+	Detected      bool          `json:"detected"`                 // Whether the secret matched a known token format
+	Provider      string        `json:"provider,omitempty"`       // Issuing service, e.g. github or aws
+	Type          string        `json:"type,omitempty"`           // Token type, e.g. "personal access token (classic)"
+	ChecksumValid *bool         `json:"checksum_valid,omitempty"` // Result of the embedded checksum, if the format has one
+	ExpiresAt     *time.Time    `json:"expires_at,omitempty"`     // Expiry decoded from the token, if present
+	Expired       *bool         `json:"expired,omitempty"`        // Whether the decoded expiry is in the past
+	Details       []TokenDetail `json:"details,omitempty"`        // Decoded fields in display order
+	Template      string        `json:"template,omitempty"`       // Template that validates this token type, if available
+}
+
+// TokenDetail is a named value decoded from a token
+type TokenDetail struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// AddDetail appends a decoded value, skipping empty ones
+func (r *TokenReport) AddDetail(name string, value string) {
+	if value == "" {
+		return
+	}
+	r.Details = append(r.Details, TokenDetail{Name: name, Value: value})
+}
+
+// IsInvalid reports whether the offline checks prove the secret unusable
+func (r *TokenReport) IsInvalid() bool {
+	return (r.ChecksumValid != nil && !*r.ChecksumValid) || (r.Expired != nil && *r.Expired)
+}
@@ -3,7 +3,11 @@ package models
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/theinfosecguy/archer/internal/constants"
 )
@@ -27,28 +31,145 @@ func (r *RequestConfig) Validate() error {
 
 // SuccessCriteria represents success criteria for validating API responses
 type SuccessCriteria struct {
-	StatusCode     []int    `yaml:"status_code" json:"status_code"`
-	RequiredFields []string `yaml:"required_fields,omitempty" json:"required_fields,omitempty"`
+	StatusCode      StatusPatterns   `yaml:"status_code" json:"status_code"` // Codes, ranges ("200-204"), classes ("2xx") or negations ("!404")
+	RequiredFields  []string         `yaml:"required_fields,omitempty" json:"required_fields,omitempty"`
+	FieldAssertions []FieldAssertion `yaml:"field_assertions,omitempty" json:"field_assertions,omitempty"`
+	RequiredHeaders []string         `yaml:"required_headers,omitempty" json:"required_headers,omitempty"`   // Response headers that must be present
+	BodyContains    []string         `yaml:"body_contains,omitempty" json:"body_contains,omitempty"`         // Substrings the body must contain
+	BodyNotContains []string         `yaml:"body_not_contains,omitempty" json:"body_not_contains,omitempty"` // Substrings the body must not contain
+	BodyRegex       []string         `yaml:"body_regex,omitempty" json:"body_regex,omitempty"`               // Regex patterns the body must match
+	ResponseFormat  string           `yaml:"response_format,omitempty" json:"response_format,omitempty"`     // json (default), text, xml or form
+	Expression      string           `yaml:"expression,omitempty" json:"expression,omitempty"`               // gval expression over status, headers and body
+}
+
+// Format returns the response format, defaulting to json
+func (s *SuccessCriteria) Format() string {
+	if s.ResponseFormat == "" {
+		return constants.ResponseFormatJSON
+	}
+	return s.ResponseFormat
+}
+
+// HasFieldChecks reports whether the criteria address fields of a decoded body
+func (s *SuccessCriteria) HasFieldChecks() bool {
+	return len(s.RequiredFields) > 0 || len(s.FieldAssertions) > 0
+}
+
+// Validate validates the success criteria
+func (s *SuccessCriteria) Validate() error {
+	if err := s.StatusCode.Validate("success_criteria.status_code"); err != nil {
+		return err
+	}
+	if s.ResponseFormat != "" && !constants.ResponseFormats[s.ResponseFormat] {
+		return fmt.Errorf(constants.ResponseFormatInvalid, s.ResponseFormat)
+	}
+	if s.HasFieldChecks() && (s.Format() == constants.ResponseFormatText || s.Format() == constants.ResponseFormatXML) {
+		return fmt.Errorf(constants.ResponseFormatNoFields, s.ResponseFormat)
+	}
+	for _, pattern := range s.BodyRegex {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf(constants.SuccessInvalidRegex, pattern, err)
+		}
+	}
+	for i := range s.FieldAssertions {
+		if err := s.FieldAssertions[i].Validate(); err != nil {
+			return err
+		}
+	}
+	if s.Expression != "" {
+		if _, err := CompileExpression(s.Expression); err != nil {
+			return fmt.Errorf(constants.ExpressionInvalid, err)
+		}
+	}
+	return nil
 }
 
 // ErrorHandling represents error handling configuration
 type ErrorHandling struct {
-	MaxRetries    int            `yaml:"max_retries" json:"max_retries"`
-	RetryDelay    int            `yaml:"retry_delay" json:"retry_delay"`
-	ErrorMessages map[int]string `yaml:"error_messages,omitempty" json:"error_messages,omitempty"`
+	MaxRetries    int                      `yaml:"max_retries" json:"max_retries"`
+	RetryDelay    Delay                    `yaml:"retry_delay" json:"retry_delay"`                             // Base delay between attempts: seconds or a duration ("500ms")
+	MaxRetryDelay Delay                    `yaml:"max_retry_delay,omitempty" json:"max_retry_delay,omitempty"` // Cap on any single wait, defaults to 30s
+	Backoff       string                   `yaml:"backoff,omitempty" json:"backoff,omitempty"`                 // fixed (default), exponential or jittered
+	RetryOn       StatusPatterns           `yaml:"retry_on,omitempty" json:"retry_on,omitempty"`               // Status patterns that are retried, defaults to 429 and 5xx
+	ErrorMessages map[StatusPattern]string `yaml:"error_messages,omitempty" json:"error_messages,omitempty"`   // Keyed by status code, range, class or negation
+}
+
+// BackoffStrategy returns the backoff strategy, defaulting to fixed
+func (e *ErrorHandling) BackoffStrategy() string {
+	if e.Backoff == "" {
+		return constants.BackoffFixed
+	}
+	return e.Backoff
+}
+
+// RetryDelayCap returns the longest single wait between attempts
+func (e *ErrorHandling) RetryDelayCap() time.Duration {
+	if e.MaxRetryDelay > 0 {
+		return e.MaxRetryDelay.Duration()
+	}
+	return time.Duration(constants.DefaultMaxRetryDelay) * time.Second
+}
+
+// RetryStatuses returns the status patterns that are retried, falling back to 429 and 5xx
+func (e *ErrorHandling) RetryStatuses() StatusPatterns {
+	if len(e.RetryOn) > 0 {
+		return e.RetryOn
+	}
+	patterns := make(StatusPatterns, 0, len(constants.DefaultRetryOn))
+	for _, pattern := range constants.DefaultRetryOn {
+		patterns = append(patterns, StatusPattern(pattern))
+	}
+	return patterns
+}
+
+// Validate validates the error handling configuration
+func (e *ErrorHandling) Validate() error {
+	if e.MaxRetries < 0 {
+		return fmt.Errorf(constants.RetryNegative, "max_retries")
+	}
+	if e.RetryDelay < 0 {
+		return fmt.Errorf(constants.RetryNegative, "retry_delay")
+	}
+	if e.MaxRetryDelay < 0 {
+		return fmt.Errorf(constants.RetryNegative, "max_retry_delay")
+	}
+	if e.Backoff != "" && !constants.BackoffStrategies[e.Backoff] {
+		return fmt.Errorf(constants.BackoffInvalid, e.Backoff)
+	}
+	if err := e.RetryOn.Validate("error_handling.retry_on"); err != nil {
+		return err
+	}
+	for _, key := range SortedStatusPatterns(e.ErrorMessages) {
+		if err := key.Validate(); err != nil {
+			return fmt.Errorf(constants.StatusPatternFieldInvalid, "error_handling.error_messages", err)
+		}
+	}
+	return nil
 }
 
 // SecretTemplate represents a template for secret validation
 type SecretTemplate struct {
-	Name              string          `yaml:"name" json:"name"`
-	Description       string          `yaml:"description" json:"description"`
-	APIURL            string          `yaml:"api_url" json:"api_url"`
-	Method            string          `yaml:"method" json:"method"`
-	Mode              string          `yaml:"mode,omitempty" json:"mode,omitempty"`
-	RequiredVariables []string        `yaml:"required_variables,omitempty" json:"required_variables,omitempty"`
-	Request           RequestConfig   `yaml:"request" json:"request"`
-	SuccessCriteria   SuccessCriteria `yaml:"success_criteria" json:"success_criteria"`
-	ErrorHandling     ErrorHandling   `yaml:"error_handling" json:"error_handling"`
+	Name              string                  `yaml:"name" json:"name"`
+	Extends           string                  `yaml:"extends,omitempty" json:"extends,omitempty"`
+	Description       string                  `yaml:"description" json:"description"`
+	APIURL            string                  `yaml:"api_url" json:"api_url"`
+	Method            string                  `yaml:"method" json:"method"`
+	Mode              string                  `yaml:"mode,omitempty" json:"mode,omitempty"`
+	RequiredVariables []string                `yaml:"required_variables,omitempty" json:"required_variables,omitempty"`
+	OptionalVariables map[string]string       `yaml:"optional_variables,omitempty" json:"optional_variables,omitempty"` // Variable name to default value
+	Variables         map[string]VariableSpec `yaml:"variables,omitempty" json:"variables,omitempty"`                   // Type, pattern, description and secrecy per variable
+	SecretFormat      *SecretFormat           `yaml:"secret_format,omitempty" json:"secret_format,omitempty"`           // Checked before any request is sent (single mode)
+	Request           RequestConfig           `yaml:"request" json:"request"`
+	SuccessCriteria   SuccessCriteria         `yaml:"success_criteria" json:"success_criteria"`
+	FailureCriteria   FailureCriteria         `yaml:"failure_criteria,omitempty" json:"failure_criteria,omitempty"`
+	Outcomes          []Outcome               `yaml:"outcomes,omitempty" json:"outcomes,omitempty"`
+	Extract           map[string]Extraction   `yaml:"extract,omitempty" json:"extract,omitempty"`
+	Scopes            *ScopeConfig            `yaml:"scopes,omitempty" json:"scopes,omitempty"`
+	Steps             []Step                  `yaml:"steps,omitempty" json:"steps,omitempty"`
+	Capabilities      []Capability            `yaml:"capabilities,omitempty" json:"capabilities,omitempty"`
+	ErrorHandling     ErrorHandling           `yaml:"error_handling" json:"error_handling"`
+	NoProxy           bool                    `yaml:"no_proxy,omitempty" json:"no_proxy,omitempty"` // Connect directly even when a proxy is configured
+	TLS               *TLSConfig              `yaml:"tls,omitempty" json:"tls,omitempty"`           // CA bundles, client certificate and pins for this provider
 }
 
 // SetDefaults sets default values for the template
@@ -66,7 +187,13 @@ func (t *SecretTemplate) SetDefaults() {
 		t.ErrorHandling.MaxRetries = constants.DefaultMaxRetries
 	}
 	if t.ErrorHandling.RetryDelay == 0 {
-		t.ErrorHandling.RetryDelay = constants.DefaultRetryDelay
+		t.ErrorHandling.RetryDelay = Seconds(constants.DefaultRetryDelay)
+	}
+	for i := range t.Steps {
+		t.Steps[i].SetDefaults(t)
+	}
+	for i := range t.Capabilities {
+		t.Capabilities[i].SetDefaults(t)
 	}
 }
 
@@ -77,6 +204,10 @@ func (t *SecretTemplate) Validate() error {
 		return fmt.Errorf(constants.ModeValidationError)
 	}
 
+	if t.APIURL == "" {
+		return fmt.Errorf(constants.APIURLRequired)
+	}
+
 	// Validate required variables format
 	for _, v := range t.RequiredVariables {
 		if !constants.UpperSnakeCasePattern.MatchString(v) {
@@ -84,6 +215,24 @@ func (t *SecretTemplate) Validate() error {
 		}
 	}
 
+	// Validate optional variables
+	for name := range t.OptionalVariables {
+		if !constants.UpperSnakeCasePattern.MatchString(name) {
+			return fmt.Errorf(constants.UpperSnakeCaseError, name)
+		}
+		if name == constants.SecretVariableName {
+			return fmt.Errorf(constants.OptionalVariableReserved)
+		}
+		if slices.Contains(t.RequiredVariables, name) {
+			return fmt.Errorf(constants.OptionalVariableConflict, name)
+		}
+	}
+
+	// Validate variable specifications
+	if err := t.validateVariableSpecs(); err != nil {
+		return err
+	}
+
 	// Validate multipart requirements
 	if t.Mode == constants.ModeMultipart && len(t.RequiredVariables) == 0 {
 		return fmt.Errorf(constants.MultipartRequiresVariables)
@@ -94,58 +243,126 @@ func (t *SecretTemplate) Validate() error {
 		return fmt.Errorf(constants.SingleModeNoVariables)
 	}
 
+	// Validate secret format
+	if t.SecretFormat != nil {
+		if t.Mode != constants.ModeSingle {
+			return fmt.Errorf(constants.SecretFormatMultipart)
+		}
+		if err := t.SecretFormat.Validate(); err != nil {
+			return err
+		}
+	}
+
 	// Validate request config
 	if err := t.Request.Validate(); err != nil {
 		return err
 	}
 
-	// Extract all variables used in template
-	usedVariables := make(map[string]bool)
+	// Validate success criteria
+	if err := t.SuccessCriteria.Validate(); err != nil {
+		return err
+	}
+
+	// Validate failure criteria
+	if err := t.FailureCriteria.Validate(); err != nil {
+		return err
+	}
+
+	// Validate error handling
+	if err := t.ErrorHandling.Validate(); err != nil {
+		return err
+	}
 
-	// Check URL
-	for _, match := range constants.VariablePattern.FindAllStringSubmatch(t.APIURL, -1) {
-		if len(match) > 1 {
-			usedVariables[match[1]] = true
+	if t.TLS != nil {
+		if err := t.TLS.Validate(); err != nil {
+			return err
 		}
 	}
 
-	// Check headers
-	for _, value := range t.Request.Headers {
-		for _, match := range constants.VariablePattern.FindAllStringSubmatch(value, -1) {
-			if len(match) > 1 {
-				usedVariables[match[1]] = true
-			}
+	// Validate outcomes
+	for i := range t.Outcomes {
+		if err := t.Outcomes[i].Validate(); err != nil {
+			return err
 		}
 	}
 
-	// Check query params
-	for _, value := range t.Request.QueryParams {
-		for _, match := range constants.VariablePattern.FindAllStringSubmatch(value, -1) {
-			if len(match) > 1 {
-				usedVariables[match[1]] = true
-			}
+	// Validate extractions
+	for name, extraction := range t.Extract {
+		if err := extraction.Validate(name); err != nil {
+			return err
 		}
 	}
 
-	// Check data
-	if t.Request.Data != nil {
-		for _, match := range constants.VariablePattern.FindAllStringSubmatch(*t.Request.Data, -1) {
-			if len(match) > 1 {
-				usedVariables[match[1]] = true
-			}
+	if t.Scopes != nil {
+		if err := t.Scopes.Validate(); err != nil {
+			return err
 		}
 	}
 
-	// Check JSON data
-	if t.Request.JSONData != nil {
-		jsonStr, _ := json.Marshal(t.Request.JSONData)
-		for _, match := range constants.VariablePattern.FindAllStringSubmatch(string(jsonStr), -1) {
-			if len(match) > 1 {
-				usedVariables[match[1]] = true
+	// Steps may use input variables and values extracted by earlier steps
+	usedVariables := make(map[string]bool)
+	stepVariables := make(map[string]bool)
+	for i := range t.Steps {
+		step := &t.Steps[i]
+		if err := step.Validate(); err != nil {
+			return err
+		}
+
+		stepUsed := make(map[string]bool)
+		collectRequestVariables(step.APIURL, step.Request, stepUsed)
+		for v := range stepUsed {
+			if stepVariables[v] {
+				continue
+			}
+			if !t.isInputVariable(v) {
+				return fmt.Errorf(constants.StepVariableUndefined, step.Name, v)
+			}
+			usedVariables[v] = true
+		}
+
+		for name := range step.Extract {
+			if t.isInputVariable(name) {
+				return fmt.Errorf(constants.StepVariableConflict, step.Name, name)
 			}
+			stepVariables[name] = true
 		}
 	}
 
+	// The main request and capability probes run after every step, so they may
+	// use any step variable
+	requestVariables := make(map[string]bool)
+	collectRequestVariables(t.APIURL, t.Request, requestVariables)
+	capabilityNames := make(map[string]bool)
+	for i := range t.Capabilities {
+		capability := &t.Capabilities[i]
+		if err := capability.Validate(); err != nil {
+			return err
+		}
+		if capabilityNames[capability.Name] {
+			return fmt.Errorf(constants.CapabilityDuplicateName, capability.Name)
+		}
+		capabilityNames[capability.Name] = true
+		collectRequestVariables(capability.APIURL, capability.Request, requestVariables)
+	}
+	for v := range requestVariables {
+		if !stepVariables[v] {
+			usedVariables[v] = true
+		}
+	}
+
+	// Optional variables may be used in either mode, but must be used
+	unusedOptional := make([]string, 0)
+	for name := range t.OptionalVariables {
+		if !usedVariables[name] {
+			unusedOptional = append(unusedOptional, name)
+		}
+		delete(usedVariables, name)
+	}
+	if len(unusedOptional) > 0 {
+		sort.Strings(unusedOptional)
+		return fmt.Errorf(constants.UnusedOptionalVariables, strings.Join(unusedOptional, ", "))
+	}
+
 	// Validate based on mode
 	if t.Mode == constants.ModeSingle {
 		// Only ${SECRET} should be used
@@ -199,9 +416,60 @@ func (t *SecretTemplate) Validate() error {
 	return nil
 }
 
+// isInputVariable reports whether name is supplied by the caller rather than
+// extracted by a step
+func (t *SecretTemplate) isInputVariable(name string) bool {
+	_, optional := t.OptionalVariables[name]
+	return name == constants.SecretVariableName || slices.Contains(t.RequiredVariables, name) || optional
+}
+
 // ValidationResult represents the result of a secret validation
 type ValidationResult struct {
-	Valid   bool   `json:"valid"`
-	Message string `json:"message,omitempty"`
-	Error   string `json:"error,omitempty"`
+	Valid           bool               `json:"valid"`
+	Status          string             `json:"status"`
+	Outcome         string             `json:"outcome,omitempty"`
+	Extracted       map[string]string  `json:"extracted,omitempty"`
+	Scopes          *ScopeReport       `json:"scopes,omitempty"`
+	Message         string             `json:"message,omitempty"`
+	Error           string             `json:"error,omitempty"`
+	FailedAssertion string             `json:"failed_assertion,omitempty"`
+	MatchedFailure  string             `json:"matched_failure,omitempty"`
+	FailedStep      string             `json:"failed_step,omitempty"`
+	Capabilities    []CapabilityResult `json:"capabilities,omitempty"`
+	Attempts        []TemplateAttempt  `json:"attempts,omitempty"`
+	RequestAttempts []RequestAttempt   `json:"request_attempts,omitempty"`
+	Variables       map[string]string  `json:"-"` // Variables the main request was sent with, including step values; never output
+}
+
+// TemplateAttempt records the result of validating a secret against one auto-detected template
+type TemplateAttempt struct {
+	Template string `json:"template"`
+	Status   string `json:"status"`
+	Outcome  string `json:"outcome,omitempty"`
+}
+
+// collectRequestVariables records the ${VAR} names used in a request URL and config
+func collectRequestVariables(apiURL string, request RequestConfig, used map[string]bool) {
+	values := []string{apiURL}
+	for _, value := range request.Headers {
+		values = append(values, value)
+	}
+	for _, value := range request.QueryParams {
+		values = append(values, value)
+	}
+	if request.Data != nil {
+		values = append(values, *request.Data)
+	}
+	if request.JSONData != nil {
+		jsonStr, _ := json.Marshal(request.JSONData)
+		values = append(values, string(jsonStr))
+	}
+
+	for _, value := range values {
+		for _, match := range constants.VariablePattern.FindAllStringSubmatch(value, -1) {
+			if len(match) > 1 {
+				used[match[1]] = true
+			}
+		}
+	}
 }
@@ -1,7 +1,12 @@
 package models
 
 import (
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestRequestConfig_Validate_BothDataAndJSONData(t *testing.T) {
@@ -120,7 +125,7 @@ func TestSecretTemplate_SetDefaults_ZeroRetryDelay(t *testing.T) {
 	template.SetDefaults()
 
 	if template.ErrorHandling.RetryDelay != 0 {
-		t.Errorf("RetryDelay = %d, want 0", template.ErrorHandling.RetryDelay)
+		t.Errorf("RetryDelay = %s, want 0", template.ErrorHandling.RetryDelay)
 	}
 }
 
@@ -134,7 +139,7 @@ func TestSecretTemplate_SetDefaults_PreservesExistingValues(t *testing.T) {
 		},
 		ErrorHandling: ErrorHandling{
 			MaxRetries: 5,
-			RetryDelay: 10,
+			RetryDelay: Seconds(10),
 		},
 	}
 
@@ -156,8 +161,8 @@ func TestSecretTemplate_SetDefaults_PreservesExistingValues(t *testing.T) {
 		t.Errorf("MaxRetries = %d, want 5", template.ErrorHandling.MaxRetries)
 	}
 
-	if template.ErrorHandling.RetryDelay != 10 {
-		t.Errorf("RetryDelay = %d, want 10", template.ErrorHandling.RetryDelay)
+	if template.ErrorHandling.RetryDelay != Seconds(10) {
+		t.Errorf("RetryDelay = %s, want 10s", template.ErrorHandling.RetryDelay)
 	}
 }
 
@@ -240,6 +245,19 @@ func TestSecretTemplate_Validate_InvalidVariableFormat(t *testing.T) {
 	}
 }
 
+func TestSecretTemplate_Validate_MissingAPIURL(t *testing.T) {
+	template := SecretTemplate{
+		Name: "base",
+		Mode: "single",
+	}
+
+	err := template.Validate()
+
+	if err == nil {
+		t.Error("Validate() error = nil, want missing api_url error")
+	}
+}
+
 func TestSecretTemplate_Validate_MultipartMissingRequiredVariables(t *testing.T) {
 	template := SecretTemplate{
 		Name:   "ghost",
@@ -527,3 +545,978 @@ func TestSecretTemplate_Validate_AllVariableLocations(t *testing.T) {
 		t.Errorf("Validate() error = %v, want nil", err)
 	}
 }
+
+func TestSecretTemplate_Validate_FieldAssertionValid(t *testing.T) {
+	minLength := 1
+	template := SecretTemplate{
+		Name:   "slack",
+		Mode:   "single",
+		APIURL: "https://slack.com/api/auth.test",
+		SuccessCriteria: SuccessCriteria{
+			StatusCode: StatusCodes(200),
+			FieldAssertions: []FieldAssertion{
+				{Path: "$.ok", Equals: true},
+				{Path: "$.scopes", Type: "array", MinLength: &minLength},
+				{Path: "$.team_id", Matches: "^T[A-Z0-9]+$"},
+			},
+		},
+	}
+
+	err := template.Validate()
+
+	if err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+}
+
+func TestSecretTemplate_Validate_FieldAssertionMissingPath(t *testing.T) {
+	template := SecretTemplate{
+		Name:   "slack",
+		Mode:   "single",
+		APIURL: "https://slack.com/api/auth.test",
+		SuccessCriteria: SuccessCriteria{
+			FieldAssertions: []FieldAssertion{{Equals: true}},
+		},
+	}
+
+	err := template.Validate()
+
+	if err == nil {
+		t.Error("Validate() error = nil, want missing path error")
+	}
+}
+
+func TestSecretTemplate_Validate_FieldAssertionInvalidType(t *testing.T) {
+	template := SecretTemplate{
+		Name:   "slack",
+		Mode:   "single",
+		APIURL: "https://slack.com/api/auth.test",
+		SuccessCriteria: SuccessCriteria{
+			FieldAssertions: []FieldAssertion{{Path: "$.ok", Type: "boolean"}},
+		},
+	}
+
+	err := template.Validate()
+
+	if err == nil {
+		t.Error("Validate() error = nil, want invalid type error")
+	}
+}
+
+func TestSecretTemplate_Validate_FieldAssertionInvalidRegex(t *testing.T) {
+	template := SecretTemplate{
+		Name:   "slack",
+		Mode:   "single",
+		APIURL: "https://slack.com/api/auth.test",
+		SuccessCriteria: SuccessCriteria{
+			FieldAssertions: []FieldAssertion{{Path: "$.team_id", Matches: "[unclosed"}},
+		},
+	}
+
+	err := template.Validate()
+
+	if err == nil {
+		t.Error("Validate() error = nil, want invalid regex error")
+	}
+}
+
+func TestSecretTemplate_Validate_FieldAssertionNoChecks(t *testing.T) {
+	template := SecretTemplate{
+		Name:   "slack",
+		Mode:   "single",
+		APIURL: "https://slack.com/api/auth.test",
+		SuccessCriteria: SuccessCriteria{
+			FieldAssertions: []FieldAssertion{{Path: "$.ok"}},
+		},
+	}
+
+	err := template.Validate()
+
+	if err == nil {
+		t.Error("Validate() error = nil, want no checks error")
+	}
+}
+
+func TestSecretTemplate_Validate_FailureCriteriaInvalidRegex(t *testing.T) {
+	template := SecretTemplate{
+		Name:   "newrelic",
+		Mode:   "single",
+		APIURL: "https://api.newrelic.com/graphql",
+		FailureCriteria: FailureCriteria{
+			BodyRegex: []string{"(unclosed"},
+		},
+	}
+
+	err := template.Validate()
+
+	if err == nil {
+		t.Error("Validate() error = nil, want invalid regex error")
+	}
+}
+
+func TestSecretTemplate_Validate_OutcomeValid(t *testing.T) {
+	template := SecretTemplate{
+		Name:   "slack",
+		Mode:   "single",
+		APIURL: "https://slack.com/api/auth.test",
+		Outcomes: []Outcome{
+			{Name: "revoked", Field: "$.error", Equals: "token_revoked"},
+			{Name: "insufficient_permissions", Status: "valid", StatusCodes: StatusCodes(403)},
+		},
+	}
+
+	err := template.Validate()
+
+	if err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+}
+
+func TestSecretTemplate_Validate_OutcomeInvalidName(t *testing.T) {
+	template := SecretTemplate{
+		Name:     "slack",
+		Mode:     "single",
+		APIURL:   "https://slack.com/api/auth.test",
+		Outcomes: []Outcome{{Name: "Token-Revoked", StatusCodes: StatusCodes(401)}},
+	}
+
+	err := template.Validate()
+
+	if err == nil {
+		t.Error("Validate() error = nil, want invalid name error")
+	}
+}
+
+func TestSecretTemplate_Validate_OutcomeNoConditions(t *testing.T) {
+	template := SecretTemplate{
+		Name:     "slack",
+		Mode:     "single",
+		APIURL:   "https://slack.com/api/auth.test",
+		Outcomes: []Outcome{{Name: "revoked"}},
+	}
+
+	err := template.Validate()
+
+	if err == nil {
+		t.Error("Validate() error = nil, want no conditions error")
+	}
+}
+
+func TestSecretTemplate_Validate_OutcomeInvalidStatus(t *testing.T) {
+	template := SecretTemplate{
+		Name:     "slack",
+		Mode:     "single",
+		APIURL:   "https://slack.com/api/auth.test",
+		Outcomes: []Outcome{{Name: "revoked", Status: "error", StatusCodes: StatusCodes(401)}},
+	}
+
+	err := template.Validate()
+
+	if err == nil {
+		t.Error("Validate() error = nil, want invalid status error")
+	}
+}
+
+func TestSecretTemplate_EffectiveOutcomes_DerivedFromErrorMessages(t *testing.T) {
+	template := SecretTemplate{
+		Outcomes: []Outcome{{Name: "revoked", Field: "$.error", Equals: "token_revoked"}},
+		SuccessCriteria: SuccessCriteria{
+			StatusCode: StatusCodes(200),
+		},
+		ErrorHandling: ErrorHandling{
+			ErrorMessages: map[StatusPattern]string{
+				"200": "Unreachable message",
+				"401": "Invalid token",
+				"429": "Rate limit exceeded",
+			},
+		},
+	}
+
+	outcomes := template.EffectiveOutcomes()
+
+	if len(outcomes) != 3 {
+		t.Fatalf("EffectiveOutcomes() length = %d, want 3", len(outcomes))
+	}
+
+	if outcomes[0].Name != "revoked" {
+		t.Errorf("outcomes[0].Name = %q, want 'revoked'", outcomes[0].Name)
+	}
+
+	if outcomes[1].Name != "unauthorized" || outcomes[1].ResolvedStatus() != "invalid" {
+		t.Errorf("outcomes[1] = %s/%s, want unauthorized/invalid", outcomes[1].Name, outcomes[1].ResolvedStatus())
+	}
+
+	if outcomes[2].Name != "rate_limited" || outcomes[2].ResolvedStatus() != "inconclusive" {
+		t.Errorf("outcomes[2] = %s/%s, want rate_limited/inconclusive", outcomes[2].Name, outcomes[2].ResolvedStatus())
+	}
+}
+
+func TestExtraction_UnmarshalYAML_ShorthandAndMapping(t *testing.T) {
+	data := []byte(`
+login: "$.login"
+email:
+  path: "$.email"
+  mask: true
+scopes:
+  header: X-OAuth-Scopes
+`)
+
+	var extract map[string]Extraction
+	if err := yaml.Unmarshal(data, &extract); err != nil {
+		t.Fatalf("yaml.Unmarshal() error = %v", err)
+	}
+
+	if extract["login"].Path != "$.login" || extract["login"].Mask {
+		t.Errorf("login = %+v, want shorthand path without mask", extract["login"])
+	}
+
+	if extract["email"].Path != "$.email" || !extract["email"].Mask {
+		t.Errorf("email = %+v, want masked path", extract["email"])
+	}
+
+	if extract["scopes"].Header != "X-OAuth-Scopes" {
+		t.Errorf("scopes = %+v, want header source", extract["scopes"])
+	}
+}
+
+func TestSecretTemplate_Validate_ExtractInvalid(t *testing.T) {
+	tests := map[string]Extraction{
+		"Login":   {Path: "$.login"},
+		"both":    {Path: "$.login", Header: "X-User"},
+		"neither": {},
+	}
+
+	for name, extraction := range tests {
+		template := SecretTemplate{
+			Name:    "github",
+			Mode:    "single",
+			APIURL:  "https://api.github.com/user",
+			Extract: map[string]Extraction{name: extraction},
+		}
+
+		if err := template.Validate(); err == nil {
+			t.Errorf("Validate() with extract %q = nil, want error", name)
+		}
+	}
+}
+
+func TestScopeConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  ScopeConfig
+		wantErr bool
+	}{
+		{"valid", ScopeConfig{Header: "X-OAuth-Scopes", RiskLevels: map[string]string{"repo": "high"}}, false},
+		{"missing header", ScopeConfig{RiskLevels: map[string]string{"repo": "high"}}, true},
+		{"invalid risk level", ScopeConfig{Header: "X-OAuth-Scopes", RiskLevels: map[string]string{"repo": "severe"}}, true},
+		{"none is not assignable", ScopeConfig{Header: "X-OAuth-Scopes", RiskLevels: map[string]string{"repo": "none"}}, true},
+		{"invalid default risk", ScopeConfig{Header: "X-OAuth-Scopes", DefaultRisk: "unknown"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScopeConfig_RiskFor(t *testing.T) {
+	config := ScopeConfig{Header: "X-OAuth-Scopes", RiskLevels: map[string]string{"repo": "high"}}
+
+	if risk := config.RiskFor("repo"); risk != "high" {
+		t.Errorf("RiskFor(repo) = %q, want high", risk)
+	}
+	if risk := config.RiskFor("gist"); risk != "medium" {
+		t.Errorf("RiskFor(gist) = %q, want default medium", risk)
+	}
+
+	config.DefaultRisk = "low"
+	if risk := config.RiskFor("gist"); risk != "low" {
+		t.Errorf("RiskFor(gist) = %q, want configured default low", risk)
+	}
+}
+
+func TestSecretTemplate_Validate_Steps(t *testing.T) {
+	newTemplate := func(steps []Step) SecretTemplate {
+		return SecretTemplate{
+			Name:   "chain",
+			Mode:   "single",
+			APIURL: "https://api.example.com/me",
+			Request: RequestConfig{
+				Headers: map[string]string{"Authorization": "Bearer ${SESSION_TOKEN}"},
+			},
+			Steps: steps,
+		}
+	}
+	loginStep := Step{
+		Name:            "login",
+		APIURL:          "https://api.example.com/session?key=${SECRET}",
+		SuccessCriteria: SuccessCriteria{StatusCode: StatusCodes(200)},
+		Extract:         map[string]Extraction{"SESSION_TOKEN": {Path: "$.token"}},
+	}
+
+	tests := []struct {
+		name    string
+		steps   []Step
+		wantErr bool
+	}{
+		{"step variables usable in main request", []Step{loginStep}, false},
+		{"main request variable without step", nil, true},
+		{"invalid step name", []Step{{Name: "Login", APIURL: loginStep.APIURL, SuccessCriteria: loginStep.SuccessCriteria, Extract: loginStep.Extract}}, true},
+		{"missing step url", []Step{{Name: "login", SuccessCriteria: loginStep.SuccessCriteria, Extract: loginStep.Extract}}, true},
+		{"missing step status code", []Step{{Name: "login", APIURL: loginStep.APIURL, Extract: loginStep.Extract}}, true},
+		{"lowercase step variable", []Step{{Name: "login", APIURL: loginStep.APIURL, SuccessCriteria: loginStep.SuccessCriteria, Extract: map[string]Extraction{"session_token": {Path: "$.token"}}}}, true},
+		{"step uses variable extracted by a later step", []Step{
+			{Name: "exchange", APIURL: "https://api.example.com/exchange?session=${SESSION_TOKEN}", SuccessCriteria: loginStep.SuccessCriteria, Extract: map[string]Extraction{"ACCESS_TOKEN": {Path: "$.token"}}},
+			loginStep,
+		}, true},
+		{"step uses variable extracted by an earlier step", []Step{
+			loginStep,
+			{Name: "exchange", APIURL: "https://api.example.com/exchange?session=${SESSION_TOKEN}", SuccessCriteria: loginStep.SuccessCriteria},
+		}, false},
+		{"step variable shadows secret", []Step{loginStep, {Name: "again", APIURL: loginStep.APIURL, SuccessCriteria: loginStep.SuccessCriteria, Extract: map[string]Extraction{"SECRET": {Path: "$.token"}}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := newTemplate(tt.steps)
+			template.SetDefaults()
+			err := template.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSecretTemplate_SetDefaults_Steps(t *testing.T) {
+	template := SecretTemplate{
+		Request: RequestConfig{Timeout: 15},
+		Steps:   []Step{{Name: "login"}, {Name: "exchange", Method: "post"}},
+	}
+
+	template.SetDefaults()
+
+	if template.Steps[0].Method != "GET" {
+		t.Errorf("Steps[0].Method = %q, want GET", template.Steps[0].Method)
+	}
+	if template.Steps[1].Method != "POST" {
+		t.Errorf("Steps[1].Method = %q, want POST", template.Steps[1].Method)
+	}
+	if template.Steps[0].Request.Timeout != 15 {
+		t.Errorf("Steps[0].Request.Timeout = %d, want inherited 15", template.Steps[0].Request.Timeout)
+	}
+}
+
+func TestCapability_DefaultsAndValidate(t *testing.T) {
+	template := SecretTemplate{
+		Name:    "github",
+		Mode:    "single",
+		APIURL:  "https://api.github.com/user",
+		Request: RequestConfig{Timeout: 10, Headers: map[string]string{"Authorization": "Bearer ${SECRET}"}},
+		Capabilities: []Capability{
+			{Name: "list_repos", APIURL: "https://api.github.com/user/repos"},
+		},
+		ErrorHandling: ErrorHandling{MaxRetries: 2},
+	}
+
+	template.SetDefaults()
+	if err := template.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	capability := template.Capabilities[0]
+	if capability.Method != "GET" || capability.Request.Timeout != 10 {
+		t.Errorf("capability defaults = %s/%ds, want GET/10s", capability.Method, capability.Request.Timeout)
+	}
+	if capability.Classify(200) != "allowed" || capability.Classify(403) != "denied" || capability.Classify(500) != "error" {
+		t.Error("Classify() does not map default allowed/denied status codes")
+	}
+
+	probe := capability.Template(&template)
+	if probe.Request.Headers["Authorization"] != "Bearer ${SECRET}" {
+		t.Errorf("probe headers = %v, want inherited Authorization", probe.Request.Headers)
+	}
+	if probe.ErrorHandling.MaxRetries != template.ErrorHandling.MaxRetries || probe.ErrorHandling.RetryDelay != template.ErrorHandling.RetryDelay {
+		t.Errorf("probe ErrorHandling = %+v, want inherited %+v", probe.ErrorHandling, template.ErrorHandling)
+	}
+}
+
+func TestCapability_ValidateErrors(t *testing.T) {
+	tests := map[string][]Capability{
+		"write method":   {{Name: "delete_repo", APIURL: "https://api.github.com/repos/x", Method: "DELETE"}},
+		"missing url":    {{Name: "list_repos"}},
+		"invalid name":   {{Name: "List Repos", APIURL: "https://api.github.com/user/repos"}},
+		"duplicate name": {{Name: "list_repos", APIURL: "https://a"}, {Name: "list_repos", APIURL: "https://b"}},
+		"undefined var":  {{Name: "list_repos", APIURL: "https://api.github.com/${ORG}/repos"}},
+	}
+
+	for name, capabilities := range tests {
+		t.Run(name, func(t *testing.T) {
+			template := SecretTemplate{
+				Name:         "github",
+				Mode:         "single",
+				APIURL:       "https://api.github.com/user",
+				Capabilities: capabilities,
+			}
+			template.SetDefaults()
+			if err := template.Validate(); err == nil {
+				t.Error("Validate() = nil, want error")
+			}
+		})
+	}
+}
+
+func TestSecretTemplate_Validate_OptionalVariables(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     string
+		required []string
+		optional map[string]string
+		url      string
+		wantErr  bool
+	}{
+		{"single mode with optional", "single", nil, map[string]string{"SITE": "datadoghq.com"}, "https://api.${SITE}/v1?key=${SECRET}", false},
+		{"multipart with optional", "multipart", []string{"API_TOKEN"}, map[string]string{"BASE_URL": "https://gitlab.com"}, "${BASE_URL}/api?token=${API_TOKEN}", false},
+		{"unused optional", "single", nil, map[string]string{"SITE": "datadoghq.com"}, "https://api.example.com?key=${SECRET}", true},
+		{"optional and required", "multipart", []string{"API_TOKEN"}, map[string]string{"API_TOKEN": "x"}, "https://api.example.com?token=${API_TOKEN}", true},
+		{"optional secret", "single", nil, map[string]string{"SECRET": "x"}, "https://api.example.com?key=${SECRET}", true},
+		{"invalid optional name", "single", nil, map[string]string{"site": "x"}, "https://api.${site}?key=${SECRET}", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := SecretTemplate{
+				Name:              "test",
+				Mode:              tt.mode,
+				APIURL:            tt.url,
+				RequiredVariables: tt.required,
+				OptionalVariables: tt.optional,
+			}
+			err := template.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVariableSpec_Check(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    VariableSpec
+		value   string
+		wantErr bool
+	}{
+		{"valid url", VariableSpec{Type: "url"}, "https://myblog.com", false},
+		{"url without scheme", VariableSpec{Type: "url"}, "myblog.com", true},
+		{"url with other scheme", VariableSpec{Type: "url"}, "ftp://myblog.com", true},
+		{"valid hostname", VariableSpec{Type: "hostname"}, "us5.datadoghq.com", false},
+		{"hostname with scheme", VariableSpec{Type: "hostname"}, "https://datadoghq.com", true},
+		{"valid uuid", VariableSpec{Type: "uuid"}, "123e4567-e89b-12d3-a456-426614174000", false},
+		{"invalid uuid", VariableSpec{Type: "uuid"}, "123e4567", true},
+		{"valid email", VariableSpec{Type: "email"}, "octocat@github.com", false},
+		{"email with display name", VariableSpec{Type: "email"}, "Octocat <octocat@github.com>", true},
+		{"valid integer", VariableSpec{Type: "integer"}, "-42", false},
+		{"invalid integer", VariableSpec{Type: "integer"}, "4.2", true},
+		{"string accepts anything", VariableSpec{Type: "string"}, "anything at all", false},
+		{"pattern match", VariableSpec{Pattern: "^[0-9a-f]{26}$"}, "22444f78447824223cefc48062", false},
+		{"pattern mismatch", VariableSpec{Pattern: "^[0-9a-f]{26}$"}, "not-hex", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spec.Check("VAR", tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Check(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSecretTemplate_Validate_VariableSpecs(t *testing.T) {
+	tests := []struct {
+		name    string
+		specs   map[string]VariableSpec
+		wantErr bool
+	}{
+		{"valid specs", map[string]VariableSpec{"BASE_URL": {Type: "url"}, "API_TOKEN": {Pattern: "^[a-z]+$"}}, false},
+		{"undeclared variable", map[string]VariableSpec{"OTHER": {Type: "url"}}, true},
+		{"invalid type", map[string]VariableSpec{"BASE_URL": {Type: "ipv6"}}, true},
+		{"invalid pattern", map[string]VariableSpec{"API_TOKEN": {Pattern: "[unclosed"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := SecretTemplate{
+				Name:              "ghost",
+				Mode:              "multipart",
+				APIURL:            "${BASE_URL}/ghost/api?key=${API_TOKEN}",
+				RequiredVariables: []string{"BASE_URL", "API_TOKEN"},
+				Variables:         tt.specs,
+			}
+			err := template.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSecretTemplate_Validate_OptionalVariableDefaults(t *testing.T) {
+	tests := []struct {
+		name         string
+		defaultValue string
+		wantErr      bool
+	}{
+		{"default matches spec", "datadoghq.eu", false},
+		{"no default", "", false},
+		{"default fails type", "https://datadoghq.eu", true},
+		{"default fails pattern", "example.com", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := SecretTemplate{
+				Name:              "datadog",
+				Mode:              "single",
+				APIURL:            "https://api.${SITE}/api/v1/validate?key=${SECRET}",
+				OptionalVariables: map[string]string{"SITE": tt.defaultValue},
+				Variables:         map[string]VariableSpec{"SITE": {Type: "hostname", Pattern: `^datadoghq\.`}},
+			}
+			err := template.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSecretTemplate_CheckVariables(t *testing.T) {
+	template := SecretTemplate{
+		RequiredVariables: []string{"BASE_URL", "API_TOKEN"},
+		Variables: map[string]VariableSpec{
+			"BASE_URL":  {Type: "url"},
+			"API_TOKEN": {Pattern: "^[0-9a-f]+$"},
+		},
+	}
+
+	errs := template.CheckVariables(map[string]string{"BASE_URL": "myblog", "API_TOKEN": "zz"})
+
+	if len(errs) != 2 {
+		t.Fatalf("CheckVariables() returned %d errors, want 2", len(errs))
+	}
+	if !strings.Contains(errs[0].Error(), "API_TOKEN") || !strings.Contains(errs[1].Error(), "BASE_URL") {
+		t.Errorf("CheckVariables() = %v, want errors in name order", errs)
+	}
+
+	if errs := template.CheckVariables(map[string]string{"BASE_URL": "https://myblog.com", "API_TOKEN": "abc123"}); len(errs) != 0 {
+		t.Errorf("CheckVariables() = %v, want no errors", errs)
+	}
+}
+
+func TestSecretTemplate_IsSecretVariable(t *testing.T) {
+	notSecret := false
+	template := SecretTemplate{
+		RequiredVariables: []string{"BASE_URL", "API_TOKEN"},
+		OptionalVariables: map[string]string{"SITE": "datadoghq.com"},
+		Variables:         map[string]VariableSpec{"BASE_URL": {Secret: &notSecret}},
+	}
+
+	expected := map[string]bool{"SECRET": true, "API_TOKEN": true, "BASE_URL": false, "SITE": false}
+	for name, want := range expected {
+		if got := template.IsSecretVariable(name); got != want {
+			t.Errorf("IsSecretVariable(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestSecretFormat_Check(t *testing.T) {
+	github := SecretFormat{Prefixes: []string{"ghp_", "github_pat_"}, MinLength: 40, Charset: "base64url"}
+
+	tests := []struct {
+		name    string
+		format  SecretFormat
+		secret  string
+		wantErr bool
+	}{
+		{"valid classic token", github, "ghp_" + strings.Repeat("a", 36), false},
+		{"valid fine-grained token", github, "github_pat_" + strings.Repeat("A_1", 20), false},
+		{"wrong prefix", github, "gho_" + strings.Repeat("a", 36), true},
+		{"too short", github, "ghp_abc", true},
+		{"charset mismatch", github, "ghp_" + strings.Repeat("a", 35) + "!", true},
+		{"too long", SecretFormat{MaxLength: 4}, "abcde", true},
+		{"hex", SecretFormat{Charset: "hex"}, "deadBEEF", false},
+		{"not hex", SecretFormat{Charset: "hex"}, "xyz", true},
+		{"pattern match", SecretFormat{Pattern: `^sk-[A-Za-z0-9]+$`}, "sk-abc123", false},
+		{"pattern mismatch", SecretFormat{Pattern: `^sk-[A-Za-z0-9]+$`}, "pk-abc123", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.format.Check(tt.secret)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Check(%q) error = %v, wantErr %v", tt.secret, err, tt.wantErr)
+			}
+			if err != nil && strings.Contains(err.Error(), tt.secret) {
+				t.Errorf("Check() error = %q, must not contain the secret", err)
+			}
+		})
+	}
+}
+
+func TestSecretTemplate_Validate_SecretFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		format  SecretFormat
+		wantErr bool
+	}{
+		{"valid format", "single", SecretFormat{Prefixes: []string{"sk_live_"}, MinLength: 24, Charset: "alphanumeric"}, false},
+		{"no checks", "single", SecretFormat{}, true},
+		{"empty prefix", "single", SecretFormat{Prefixes: []string{""}}, true},
+		{"min above max", "single", SecretFormat{MinLength: 10, MaxLength: 5}, true},
+		{"unknown charset", "single", SecretFormat{Charset: "emoji"}, true},
+		{"invalid pattern", "single", SecretFormat{Pattern: "[unclosed"}, true},
+		{"multipart mode", "multipart", SecretFormat{MinLength: 10}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := SecretTemplate{
+				Name:         "test",
+				Mode:         tt.mode,
+				APIURL:       "https://api.example.com/${SECRET}",
+				SecretFormat: &tt.format,
+			}
+			if tt.mode == "multipart" {
+				template.APIURL = "https://api.example.com/${API_KEY}"
+				template.RequiredVariables = []string{"API_KEY"}
+			}
+			err := template.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStatusPatterns_Matches(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns StatusPatterns
+		code     int
+		want     bool
+	}{
+		{"exact match", StatusPatterns{"200"}, 200, true},
+		{"exact mismatch", StatusPatterns{"200"}, 201, false},
+		{"range inclusive low", StatusPatterns{"200-204"}, 200, true},
+		{"range inclusive high", StatusPatterns{"200-204"}, 204, true},
+		{"range outside", StatusPatterns{"200-204"}, 205, false},
+		{"class", StatusPatterns{"2xx"}, 299, true},
+		{"class uppercase", StatusPatterns{"4XX"}, 404, true},
+		{"class outside", StatusPatterns{"2xx"}, 300, false},
+		{"negation only", StatusPatterns{"!404"}, 500, true},
+		{"negation excludes", StatusPatterns{"!404"}, 404, false},
+		{"class minus code", StatusPatterns{"2xx", "!204"}, 204, false},
+		{"class minus code keeps rest", StatusPatterns{"2xx", "!204"}, 200, true},
+		{"empty list", StatusPatterns{}, 200, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.patterns.Matches(tt.code); got != tt.want {
+				t.Errorf("%v.Matches(%d) = %v, want %v", tt.patterns, tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusPattern_Validate(t *testing.T) {
+	valid := []StatusPattern{"200", "2xx", "200-204", "!404", "! 5xx"}
+	for _, pattern := range valid {
+		if err := pattern.Validate(); err != nil {
+			t.Errorf("Validate(%q) error = %v, want nil", pattern, err)
+		}
+	}
+
+	invalid := []StatusPattern{"", "abc", "20", "600", "6xx", "204-200", "2xx-3xx", "!!404", "200-"}
+	for _, pattern := range invalid {
+		if err := pattern.Validate(); err == nil {
+			t.Errorf("Validate(%q) error = nil, want error", pattern)
+		}
+	}
+}
+
+func TestSecretTemplate_StatusPatternsFromYAML(t *testing.T) {
+	data := []byte(`
+name: test
+api_url: https://api.example.com/${SECRET}
+success_criteria:
+  status_code: [200, "201-204"]
+error_handling:
+  retry_on: ["429", "502-504"]
+  error_messages:
+    401: "Invalid token"
+    4xx: "Client error"
+`)
+
+	var template SecretTemplate
+	if err := yaml.Unmarshal(data, &template); err != nil {
+		t.Fatalf("yaml.Unmarshal() error = %v", err)
+	}
+	template.SetDefaults()
+	if err := template.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v, want nil", err)
+	}
+
+	if !template.SuccessCriteria.StatusCode.Matches(203) {
+		t.Errorf("StatusCode %v does not match 203", template.SuccessCriteria.StatusCode)
+	}
+	if template.ErrorHandling.RetryStatuses().Matches(500) {
+		t.Errorf("RetryOn %v matches 500, want only 429 and 502-504", template.ErrorHandling.RetryOn)
+	}
+
+	outcomes := template.ErrorOutcomes()
+	if len(outcomes) != 2 || outcomes[0].Name != "unauthorized" || outcomes[1].Name != "" {
+		t.Fatalf("ErrorOutcomes() = %+v, want unauthorized then the unnamed 4xx outcome", outcomes)
+	}
+	if resolved := outcomes[1].ForStatusCode(403); resolved.Name != "insufficient_permissions" || resolved.ResolvedStatus() != "invalid" {
+		t.Errorf("ForStatusCode(403) = %s/%s, want insufficient_permissions/invalid", resolved.Name, resolved.ResolvedStatus())
+	}
+}
+
+func TestSecretTemplate_Validate_MalformedStatusPatterns(t *testing.T) {
+	tests := []struct {
+		name     string
+		mutate   func(*SecretTemplate)
+		contains string
+	}{
+		{"success criteria", func(t *SecretTemplate) { t.SuccessCriteria.StatusCode = StatusPatterns{"2xy"} }, "success_criteria.status_code"},
+		{"retry_on", func(t *SecretTemplate) { t.ErrorHandling.RetryOn = StatusPatterns{"700"} }, "retry_on"},
+		{"error message key", func(t *SecretTemplate) {
+			t.ErrorHandling.ErrorMessages = map[StatusPattern]string{"4xx-5xx": "bad"}
+		}, "error_messages"},
+		{"outcome", func(t *SecretTemplate) {
+			t.Outcomes = []Outcome{{Name: "gone", StatusCodes: StatusPatterns{"41O"}}}
+		}, "outcome 'gone'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := SecretTemplate{
+				Name:            "test",
+				Mode:            "single",
+				APIURL:          "https://api.example.com/${SECRET}",
+				SuccessCriteria: SuccessCriteria{StatusCode: StatusCodes(200)},
+			}
+			tt.mutate(&template)
+			err := template.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Validate() error = %v, want error mentioning %q", err, tt.contains)
+			}
+		})
+	}
+}
+
+func TestSecretTemplate_Validate_Expression(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		wantErr    bool
+	}{
+		{"valid", `status == 200 && body.ok == true && len(body.scopes) > 0`, false},
+		{"header regex", `headers["x-oauth-scopes"] =~ "repo"`, false},
+		{"syntax error", `status ==`, true},
+		{"unbalanced parentheses", `(status == 200`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := SecretTemplate{
+				Name:   "test",
+				Mode:   "single",
+				APIURL: "https://api.example.com/${SECRET}",
+				SuccessCriteria: SuccessCriteria{
+					StatusCode: StatusCodes(200),
+					Expression: tt.expression,
+				},
+			}
+			err := template.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "success_criteria.expression") {
+				t.Errorf("Validate() error = %v, want it to name success_criteria.expression", err)
+			}
+		})
+	}
+}
+
+func TestSecretTemplate_Validate_ResponseFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		criteria SuccessCriteria
+		wantErr  bool
+	}{
+		{"default json with fields", SuccessCriteria{RequiredFields: []string{"$.ok"}}, false},
+		{"form with fields", SuccessCriteria{ResponseFormat: "form", RequiredFields: []string{"$.access_token"}}, false},
+		{"text with body checks", SuccessCriteria{ResponseFormat: "text", BodyContains: []string{"OK"}, BodyRegex: []string{"^user=\\w+"}}, false},
+		{"unknown format", SuccessCriteria{ResponseFormat: "yaml"}, true},
+		{"text with fields", SuccessCriteria{ResponseFormat: "text", RequiredFields: []string{"$.ok"}}, true},
+		{"xml with assertions", SuccessCriteria{ResponseFormat: "xml", FieldAssertions: []FieldAssertion{{Path: "$.ok", Equals: true}}}, true},
+		{"invalid body regex", SuccessCriteria{BodyRegex: []string{"("}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.criteria.StatusCode = StatusCodes(200)
+			template := SecretTemplate{
+				Name:            "test",
+				Mode:            "single",
+				APIURL:          "https://api.example.com/${SECRET}",
+				SuccessCriteria: tt.criteria,
+			}
+			err := template.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDelay_UnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Delay
+		wantErr bool
+	}{
+		{"retry_delay: 2", Seconds(2), false},
+		{"retry_delay: 0.5", Delay(500 * time.Millisecond), false},
+		{"retry_delay: 250ms", Delay(250 * time.Millisecond), false},
+		{"retry_delay: 1m", Delay(time.Minute), false},
+		{"retry_delay: soon", 0, true},
+		{"retry_delay: [1]", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var handling ErrorHandling
+			err := yaml.Unmarshal([]byte(tt.input), &handling)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("yaml.Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && handling.RetryDelay != tt.want {
+				t.Errorf("RetryDelay = %s, want %s", handling.RetryDelay, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorHandling_Validate_Backoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		handling ErrorHandling
+		wantErr  bool
+	}{
+		{"defaults", ErrorHandling{}, false},
+		{"jittered with cap", ErrorHandling{MaxRetries: 3, RetryDelay: Seconds(1), Backoff: "jittered", MaxRetryDelay: Seconds(10)}, false},
+		{"unknown backoff", ErrorHandling{Backoff: "linear"}, true},
+		{"negative retries", ErrorHandling{MaxRetries: -1}, true},
+		{"negative delay", ErrorHandling{RetryDelay: -Seconds(1)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.handling.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTLSConfig_Validate(t *testing.T) {
+	validPin := "sha256/" + strings.Repeat("A", 43) + "="
+	tests := []struct {
+		name    string
+		config  TLSConfig
+		wantErr bool
+	}{
+		{"empty", TLSConfig{}, false},
+		{"full", TLSConfig{CAFiles: []string{"ca.pem"}, CertFile: "client.pem", KeyFile: "client.key", MinVersion: "1.3", Pins: []string{validPin}}, false},
+		{"pin without prefix", TLSConfig{Pins: []string{strings.TrimPrefix(validPin, "sha256/")}}, false},
+		{"unknown min version", TLSConfig{MinVersion: "1.4"}, true},
+		{"key without cert", TLSConfig{KeyFile: "client.key"}, true},
+		{"pin not base64", TLSConfig{Pins: []string{"sha256/not-a-pin"}}, true},
+		{"pin wrong length", TLSConfig{Pins: []string{"sha256/AAAA"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTLSConfig_Merge(t *testing.T) {
+	global := TLSConfig{
+		CAFiles:            []string{"corp-ca.pem"},
+		CertFile:           "global.pem",
+		MinVersion:         "1.3",
+		Pins:               []string{"global-pin"},
+		InsecureSkipVerify: true,
+	}
+	template := &TLSConfig{
+		CAFiles:    []string{"ghe-ca.pem"},
+		CertFile:   "ghe.pem",
+		KeyFile:    "ghe.key",
+		MinVersion: "1.2",
+		Pins:       []string{"ghe-pin"},
+	}
+
+	merged := global.Merge(template)
+	want := TLSConfig{
+		CAFiles:            []string{"corp-ca.pem", "ghe-ca.pem"},
+		CertFile:           "ghe.pem",
+		KeyFile:            "ghe.key",
+		MinVersion:         "1.3",
+		Pins:               []string{"ghe-pin"},
+		InsecureSkipVerify: true,
+	}
+	if !reflect.DeepEqual(merged, want) {
+		t.Errorf("Merge() = %+v, want %+v", merged, want)
+	}
+	if len(global.CAFiles) != 1 {
+		t.Errorf("Merge() modified the global CA files: %v", global.CAFiles)
+	}
+	if got := (TLSConfig{}).Merge(&TLSConfig{MinVersion: "1.3"}); got.MinVersion != "1.3" {
+		t.Errorf("Merge() MinVersion = %q, want the template's 1.3", got.MinVersion)
+	}
+	if got := (TLSConfig{}).Merge(&TLSConfig{MinVersion: "1.0"}); got.MinVersion != "" {
+		t.Errorf("Merge() MinVersion = %q, want templates unable to go below the TLS 1.2 default", got.MinVersion)
+	}
+	if got := (TLSConfig{MinVersion: "1.0"}).Merge(&TLSConfig{MinVersion: "1.1"}); got.MinVersion != "1.1" {
+		t.Errorf("Merge() MinVersion = %q, want the template's 1.1 over an explicit 1.0", got.MinVersion)
+	}
+}
+
+func TestSecretTemplate_TLSCannotDisableVerification(t *testing.T) {
+	var template SecretTemplate
+	input := "tls:\n  min_version: \"1.2\"\n  insecure_skip_verify: true\n"
+	if err := yaml.Unmarshal([]byte(input), &template); err != nil {
+		t.Fatalf("yaml.Unmarshal() error = %v", err)
+	}
+	if template.TLS == nil || template.TLS.MinVersion != "1.2" {
+		t.Fatalf("TLS = %+v, want min_version 1.2", template.TLS)
+	}
+	if template.TLS.InsecureSkipVerify {
+		t.Error("templates must not be able to disable certificate verification")
+	}
+}
@@ -0,0 +1,152 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+)
+
+// Outcome maps a response pattern to a named validation state such as
+// expired, revoked, insufficient_permissions, rate_limited or account_suspended.
+// Every condition that is set must match for the outcome to apply.
+type Outcome struct {
+	Name         string         `yaml:"name" json:"name"`                                       // Outcome name reported in output
+	Message      string         `yaml:"message,omitempty" json:"message,omitempty"`             // Human-readable message for the outcome
+	Status       string         `yaml:"status,omitempty" json:"status,omitempty"`               // Validation status (defaults to invalid)
+	StatusCodes  StatusPatterns `yaml:"status_code,omitempty" json:"status_code,omitempty"`     // Matching HTTP status codes, ranges or classes
+	BodyContains string         `yaml:"body_contains,omitempty" json:"body_contains,omitempty"` // Matching body substring
+	BodyRegex    string         `yaml:"body_regex,omitempty" json:"body_regex,omitempty"`       // Matching body regex
+	Field        string         `yaml:"field,omitempty" json:"field,omitempty"`                 // JSONPath field that must be present
+	Equals       any            `yaml:"equals,omitempty" json:"equals,omitempty"`               // Value the field must equal
+}
+
+// Validate validates the outcome definition
+func (o *Outcome) Validate() error {
+	if !constants.SnakeCaseNamePattern.MatchString(o.Name) {
+		return fmt.Errorf(constants.OutcomeInvalidName, o.Name)
+	}
+
+	if o.Status != "" && !constants.ResponseStatuses[o.Status] {
+		return fmt.Errorf(constants.OutcomeInvalidStatus, o.Name, o.Status)
+	}
+
+	if o.BodyRegex != "" {
+		if _, err := regexp.Compile(o.BodyRegex); err != nil {
+			return fmt.Errorf(constants.OutcomeInvalidRegex, o.Name, err)
+		}
+	}
+
+	if err := o.StatusCodes.Validate(fmt.Sprintf("outcome '%s' status_code", o.Name)); err != nil {
+		return err
+	}
+
+	if o.Equals != nil && o.Field == "" {
+		return fmt.Errorf(constants.OutcomeEqualsWithoutField, o.Name)
+	}
+
+	if len(o.StatusCodes) == 0 && o.BodyContains == "" && o.BodyRegex == "" && o.Field == "" {
+		return fmt.Errorf(constants.OutcomeNoConditions, o.Name)
+	}
+
+	return nil
+}
+
+// ResolvedStatus returns the validation status for the outcome.
+// Without an explicit status, transient outcomes are inconclusive and all others invalid.
+func (o *Outcome) ResolvedStatus() string {
+	if o.Status != "" {
+		return o.Status
+	}
+	if o.Name == constants.OutcomeRateLimited || o.Name == constants.OutcomeServerError {
+		return constants.StatusInconclusive
+	}
+	return constants.StatusInvalid
+}
+
+// Conditions returns a human-readable description of the outcome conditions
+func (o Outcome) Conditions() string {
+	conditions := make([]string, 0)
+	if len(o.StatusCodes) > 0 {
+		conditions = append(conditions, fmt.Sprintf("status_code %v", o.StatusCodes))
+	}
+	if o.BodyContains != "" {
+		conditions = append(conditions, fmt.Sprintf("body contains '%s'", o.BodyContains))
+	}
+	if o.BodyRegex != "" {
+		conditions = append(conditions, fmt.Sprintf("body matches /%s/", o.BodyRegex))
+	}
+	if o.Field != "" {
+		if o.Equals != nil {
+			conditions = append(conditions, fmt.Sprintf("%s == %v", o.Field, o.Equals))
+		} else {
+			conditions = append(conditions, fmt.Sprintf("%s present", o.Field))
+		}
+	}
+	return strings.Join(conditions, ", ")
+}
+
+// EffectiveOutcomes returns the template outcomes followed by outcomes derived
+// from error_handling.error_messages, so both are resolved through one lookup.
+func (t *SecretTemplate) EffectiveOutcomes() []Outcome {
+	errorOutcomes := t.ErrorOutcomes()
+	outcomes := make([]Outcome, 0, len(t.Outcomes)+len(errorOutcomes))
+	outcomes = append(outcomes, t.Outcomes...)
+	return append(outcomes, errorOutcomes...)
+}
+
+// ErrorOutcomes returns the outcomes derived from error_handling.error_messages,
+// exact codes first. Outcomes for exact codes are named after well-known status
+// codes (e.g. 429 -> rate_limited); outcomes for ranges and classes have no name
+// or status and are resolved from the actual response code with ForStatusCode.
+func (t *SecretTemplate) ErrorOutcomes() []Outcome {
+	outcomes := make([]Outcome, 0, len(t.ErrorHandling.ErrorMessages))
+
+	for _, key := range SortedStatusPatterns(t.ErrorHandling.ErrorMessages) {
+		outcome := Outcome{
+			Message:     t.ErrorHandling.ErrorMessages[key],
+			StatusCodes: StatusPatterns{key},
+		}
+		if code, exact := key.Code(); exact {
+			// Messages for success codes were never reachable; keep it that way
+			if t.SuccessCriteria.StatusCode.Matches(code) {
+				continue
+			}
+			outcome.Name = OutcomeNameForStatusCode(code)
+			outcome.Status = StatusForHTTPCode(code)
+		}
+		outcomes = append(outcomes, outcome)
+	}
+
+	return outcomes
+}
+
+// ForStatusCode fills in the name and status of an outcome derived from a
+// status range or class, using the status code of the response it matched
+func (o Outcome) ForStatusCode(statusCode int) Outcome {
+	if o.Name == "" {
+		o.Name = OutcomeNameForStatusCode(statusCode)
+	}
+	if o.Status == "" {
+		o.Status = StatusForHTTPCode(statusCode)
+	}
+	return o
+}
+
+// OutcomeNameForStatusCode returns the outcome name for an unexpected HTTP status code
+func OutcomeNameForStatusCode(statusCode int) string {
+	if name, ok := constants.StatusCodeOutcomes[statusCode]; ok {
+		return name
+	}
+	return fmt.Sprintf(constants.OutcomeHTTPStatus, statusCode)
+}
+
+// StatusForHTTPCode classifies an unexpected HTTP status code.
+// Rate limiting and server errors say nothing about the secret itself.
+func StatusForHTTPCode(statusCode int) string {
+	if statusCode == 429 || statusCode >= 500 {
+		return constants.StatusInconclusive
+	}
+	return constants.StatusInvalid
+}
@@ -7,12 +7,14 @@ type ValidationRequestMeta struct {
 	Template             string            `json:"template"`                      // Template identifier provided by user (name or file path)
 	ResolvedTemplateName *string           `json:"resolved_template_name"`        // Template name as defined inside the template file
 	Mode                 *string           `json:"mode"`                          // Template mode if template resolved
-	Source               *string           `json:"source"`                        // Where the template was loaded from ("builtin" or "file")
+	Source               *string           `json:"source"`                        // Where the template was loaded from ("builtin", "user", "project", "env", "flag" or "file")
 	Method               *string           `json:"method"`                        // HTTP method used for validation request
 	APIURLMasked         *string           `json:"api_url_masked"`                // Masked API URL with variables hidden
 	HeadersMasked        map[string]string `json:"headers_masked,omitempty"`      // Masked request headers
 	QueryParamsMasked    map[string]string `json:"query_params_masked,omitempty"` // Masked query parameters
 	VariablesProvided    []string          `json:"variables_provided,omitempty"`  // Names of variables provided (values omitted)
+	AutoDetected         bool              `json:"auto_detected,omitempty"`       // Template was chosen with --auto
+	Candidates           []string          `json:"candidates,omitempty"`          // Templates whose secret_format matched, in rank order
 	StartedAt            time.Time         `json:"started_at"`                    // UTC timestamp when validation started
 	FinishedAt           time.Time         `json:"finished_at"`                   // UTC timestamp when validation finished
 	DurationMS           float64           `json:"duration_ms"`                   // Total duration in milliseconds
@@ -20,10 +22,20 @@ type ValidationRequestMeta struct {
 
 // ValidationResponseMeta represents metadata about the validation response
 type ValidationResponseMeta struct {
-	StatusCode            *int     `json:"status_code,omitempty"`             // HTTP status code returned by the endpoint if request executed
-	RequiredFieldsChecked []string `json:"required_fields_checked,omitempty"` // List of JSONPath fields checked, if any
-	FailedRequiredField   *string  `json:"failed_required_field,omitempty"`   // First required field that was missing, if applicable
-	Error                 *string  `json:"error,omitempty"`                   // Low-level error encountered before or during request execution
+	StatusCode            *int               `json:"status_code,omitempty"`             // HTTP status code returned by the endpoint if request executed
+	RequiredFieldsChecked []string           `json:"required_fields_checked,omitempty"` // List of JSONPath fields checked, if any
+	FailedRequiredField   *string            `json:"failed_required_field,omitempty"`   // First required field that was missing, if applicable
+	AssertionsChecked     []string           `json:"assertions_checked,omitempty"`      // Field assertions evaluated, if any
+	FailedAssertion       *string            `json:"failed_assertion,omitempty"`        // First field assertion that failed, if applicable
+	MatchedFailure        *string            `json:"matched_failure,omitempty"`         // Failure criterion that matched the response, if applicable
+	FailedStep            *string            `json:"failed_step,omitempty"`             // Name of the chain step that failed, if applicable
+	Capabilities          []CapabilityResult `json:"capabilities,omitempty"`            // Capability matrix from --probe, if requested
+	Attempts              []TemplateAttempt  `json:"attempts,omitempty"`                // Candidate templates tried with --auto, in order
+	RequestAttempts       []RequestAttempt   `json:"request_attempts,omitempty"`        // Every HTTP attempt made, including retries and steps
+	Extracted             map[string]string  `json:"extracted,omitempty"`               // Values extracted from a successful response (masked fields hidden)
+	Scopes                []ScopeGrant       `json:"scopes,omitempty"`                  // Scopes granted to the secret, with their risk levels
+	PrivilegeLevel        *string            `json:"privilege_level,omitempty"`         // Highest risk level among granted scopes, if reported
+	Error                 *string            `json:"error,omitempty"`                   // Low-level error encountered before or during request execution
 }
 
 // ValidationResultJSON represents the top-level JSON output for validate command
@@ -31,8 +43,34 @@ type ValidationResultJSON struct {
 	Command  string                 `json:"command"`           // Always "validate"
 	Version  string                 `json:"version"`           // Archer version
 	Valid    bool                   `json:"valid"`             // Indicates whether the secret validation succeeded
+	Status   string                 `json:"status"`            // Validation status: valid, invalid, inconclusive or error
+	Outcome  *string                `json:"outcome,omitempty"` // Named outcome (e.g. revoked, expired, rate_limited) if determined
 	Message  *string                `json:"message,omitempty"` // Success message when valid is true
 	Error    *string                `json:"error,omitempty"`   // Error message when valid is false
 	Request  ValidationRequestMeta  `json:"request"`           // Request metadata
 	Response ValidationResponseMeta `json:"response"`          // Response metadata
 }
+
+// InspectResultJSON represents the top-level JSON output for the inspect command
+type InspectResultJSON struct {
+	Command string `json:"command"` // Always "inspect"
+	Version string `json:"version"` // Archer version
+	TokenReport
+}
+
+// IdentifyResultJSON represents the top-level JSON output for the identify command
+type IdentifyResultJSON struct {
+	Command         string              `json:"command"`                    // Always "identify"
+	Version         string              `json:"version"`                    // Archer version
+	Candidates      []TemplateCandidate `json:"candidates"`                 // Matching templates, most specific first
+	MatchedTemplate *string             `json:"matched_template,omitempty"` // Template that reported the secret valid, with --validate
+	Attempts        []TemplateAttempt   `json:"attempts,omitempty"`         // Candidate templates tried with --validate, in order
+}
+
+// TemplateCandidate describes a template whose secret_format matches a secret
+type TemplateCandidate struct {
+	Name        string `json:"name"`
+	Source      string `json:"source"`
+	Score       int    `json:"score"`
+	Description string `json:"description,omitempty"`
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+)
+
+// Delay is a duration written in templates as seconds (1, 0.5) or as a Go
+// duration string ("500ms", "2s")
+type Delay time.Duration
+
+// Seconds returns a Delay of n whole seconds
+func Seconds(n int) Delay {
+	return Delay(time.Duration(n) * time.Second)
+}
+
+// Duration returns the delay as a time.Duration
+func (d Delay) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+// String formats the delay as a Go duration string
+func (d Delay) String() string {
+	return time.Duration(d).String()
+}
+
+// UnmarshalYAML accepts a number of seconds or a duration string
+func (d *Delay) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf(constants.DelayInvalid, node.Value)
+	}
+	if seconds, err := strconv.ParseFloat(node.Value, 64); err == nil {
+		*d = Delay(seconds * float64(time.Second))
+		return nil
+	}
+	duration, err := time.ParseDuration(node.Value)
+	if err != nil {
+		return fmt.Errorf(constants.DelayInvalid, node.Value)
+	}
+	*d = Delay(duration)
+	return nil
+}
+
+// MarshalJSON writes the delay as a duration string
+func (d Delay) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// RequestAttempt records one HTTP attempt made while validating a secret
+type RequestAttempt struct {
+	Step         string `json:"step,omitempty"`           // Chain step the attempt belongs to, empty for the main request
+	Attempt      int    `json:"attempt"`                  // 1-based attempt number within the request
+	Route        string `json:"route,omitempty"`          // "direct", or the proxy the request went through (password redacted)
+	StatusCode   int    `json:"status_code,omitempty"`    // HTTP status code, if a response was received
+	DurationMs   int64  `json:"duration_ms"`              // Time the attempt took
+	Error        string `json:"error,omitempty"`          // Transport error, if the attempt failed (secrets masked)
+	RetryDelayMs *int64 `json:"retry_delay_ms,omitempty"` // Wait before the next attempt, if one followed
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+)
+
+// ScopeConfig describes how to read the scopes granted to a secret from a
+// response header (e.g. GitHub's X-OAuth-Scopes) and how risky each scope is.
+type ScopeConfig struct {
+	Header      string            `yaml:"header" json:"header"`                                 // Response header listing granted scopes
+	Separator   string            `yaml:"separator,omitempty" json:"separator,omitempty"`       // List separator, defaults to ","
+	RiskLevels  map[string]string `yaml:"risk_levels,omitempty" json:"risk_levels,omitempty"`   // Scope name to risk level
+	DefaultRisk string            `yaml:"default_risk,omitempty" json:"default_risk,omitempty"` // Risk level for scopes not listed, defaults to medium
+}
+
+// Validate validates the scope configuration
+func (s *ScopeConfig) Validate() error {
+	if s.Header == "" {
+		return fmt.Errorf(constants.ScopesHeaderRequired)
+	}
+	for scope, risk := range s.RiskLevels {
+		if !isScopeRiskLevel(risk) {
+			return fmt.Errorf(constants.ScopesInvalidRiskLevel, scope, risk)
+		}
+	}
+	if s.DefaultRisk != "" && !isScopeRiskLevel(s.DefaultRisk) {
+		return fmt.Errorf(constants.ScopesInvalidRiskLevel, "default_risk", s.DefaultRisk)
+	}
+	return nil
+}
+
+// ListSeparator returns the separator used to split the scope header
+func (s *ScopeConfig) ListSeparator() string {
+	if s.Separator == "" {
+		return constants.DefaultScopeSeparator
+	}
+	return s.Separator
+}
+
+// RiskFor returns the risk level of a scope, falling back to the default risk
+func (s *ScopeConfig) RiskFor(scope string) string {
+	if risk, ok := s.RiskLevels[scope]; ok {
+		return risk
+	}
+	return s.UnlistedRisk()
+}
+
+// UnlistedRisk returns the risk level assigned to scopes missing from risk_levels
+func (s *ScopeConfig) UnlistedRisk() string {
+	if s.DefaultRisk != "" {
+		return s.DefaultRisk
+	}
+	return constants.DefaultScopeRisk
+}
+
+// ScopeGrant is a single scope granted to a secret
+type ScopeGrant struct {
+	Name string `json:"name"`
+	Risk string `json:"risk"`
+}
+
+// ScopeReport lists the scopes granted to a secret and the resulting privilege level
+type ScopeReport struct {
+	Scopes         []ScopeGrant `json:"scopes"`
+	PrivilegeLevel string       `json:"privilege_level"`
+}
+
+// isScopeRiskLevel reports whether level can be assigned to a scope ("none" is computed only)
+func isScopeRiskLevel(level string) bool {
+	rank, ok := constants.RiskLevelRanks[level]
+	return ok && rank > 0
+}
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+)
+
+// SecretFormat describes the shape a secret must have before it is sent to the provider
+type SecretFormat struct {
+	Prefixes  []string `yaml:"prefixes,omitempty" json:"prefixes,omitempty"`     // The secret must start with one of these
+	MinLength int      `yaml:"min_length,omitempty" json:"min_length,omitempty"` // Minimum length of the whole secret
+	MaxLength int      `yaml:"max_length,omitempty" json:"max_length,omitempty"` // Maximum length of the whole secret
+	Charset   string   `yaml:"charset,omitempty" json:"charset,omitempty"`       // Allowed characters after the prefix
+	Pattern   string   `yaml:"pattern,omitempty" json:"pattern,omitempty"`       // Regex the whole secret must match
+}
+
+// Validate validates the secret format
+func (f *SecretFormat) Validate() error {
+	if len(f.Prefixes) == 0 && f.MinLength == 0 && f.MaxLength == 0 && f.Charset == "" && f.Pattern == "" {
+		return fmt.Errorf(constants.SecretFormatNoChecks)
+	}
+	if slices.Contains(f.Prefixes, "") {
+		return fmt.Errorf(constants.SecretFormatEmptyPrefix)
+	}
+	if f.MinLength < 0 || f.MaxLength < 0 || (f.MaxLength > 0 && f.MinLength > f.MaxLength) {
+		return fmt.Errorf(constants.SecretFormatInvalidLength)
+	}
+	if f.Charset != "" {
+		if _, ok := constants.SecretCharsets[f.Charset]; !ok {
+			return fmt.Errorf(constants.SecretFormatInvalidCharset, f.Charset)
+		}
+	}
+	if f.Pattern != "" {
+		if _, err := regexp.Compile(f.Pattern); err != nil {
+			return fmt.Errorf(constants.SecretFormatInvalidPattern, err)
+		}
+	}
+	return nil
+}
+
+// Check verifies that a secret has the expected format. The error never
+// includes the secret itself.
+func (f *SecretFormat) Check(secret string) error {
+	rest := secret
+	if len(f.Prefixes) > 0 {
+		prefix, ok := f.matchPrefix(secret)
+		if !ok {
+			return fmt.Errorf(constants.SecretFormatPrefixMismatch, strings.Join(f.Prefixes, ", "))
+		}
+		rest = secret[len(prefix):]
+	}
+
+	length := len([]rune(secret))
+	if f.MinLength > 0 && length < f.MinLength {
+		return fmt.Errorf(constants.SecretFormatTooShort, length, f.MinLength)
+	}
+	if f.MaxLength > 0 && length > f.MaxLength {
+		return fmt.Errorf(constants.SecretFormatTooLong, length, f.MaxLength)
+	}
+
+	if f.Charset != "" && !constants.SecretCharsets[f.Charset].MatchString(rest) {
+		return fmt.Errorf(constants.SecretFormatCharsetMismatch, f.Charset)
+	}
+
+	if f.Pattern != "" && !regexp.MustCompile(f.Pattern).MatchString(secret) {
+		return fmt.Errorf(constants.SecretFormatPatternMismatch, f.Pattern)
+	}
+	return nil
+}
+
+// Specificity scores how precisely the format describes a secret it accepts.
+// Longer matching prefixes and stricter checks score higher.
+func (f *SecretFormat) Specificity(secret string) int {
+	score := 0
+	if prefix, ok := f.matchPrefix(secret); ok {
+		score += len(prefix) * constants.SpecificityPrefixChar
+	}
+	if f.Pattern != "" {
+		score += constants.SpecificityPattern
+	}
+	if f.Charset != "" {
+		score += constants.SpecificityCharset
+	}
+	if f.MinLength > 0 {
+		score += constants.SpecificityLengthBound
+	}
+	if f.MaxLength > 0 {
+		score += constants.SpecificityLengthBound
+	}
+	return score
+}
+
+// Describe returns a short description of the format checks
+func (f *SecretFormat) Describe() []string {
+	lines := make([]string, 0, 4)
+	if len(f.Prefixes) > 0 {
+		lines = append(lines, "Prefixes: "+strings.Join(f.Prefixes, ", "))
+	}
+	switch {
+	case f.MinLength > 0 && f.MaxLength > 0:
+		lines = append(lines, fmt.Sprintf("Length: %d-%d", f.MinLength, f.MaxLength))
+	case f.MinLength > 0:
+		lines = append(lines, fmt.Sprintf("Length: at least %d", f.MinLength))
+	case f.MaxLength > 0:
+		lines = append(lines, fmt.Sprintf("Length: at most %d", f.MaxLength))
+	}
+	if f.Charset != "" {
+		lines = append(lines, "Charset: "+f.Charset)
+	}
+	if f.Pattern != "" {
+		lines = append(lines, "Pattern: "+f.Pattern)
+	}
+	return lines
+}
+
+// matchPrefix returns the longest declared prefix the secret starts with
+func (f *SecretFormat) matchPrefix(secret string) (string, bool) {
+	matched := ""
+	for _, prefix := range f.Prefixes {
+		if strings.HasPrefix(secret, prefix) && len(prefix) > len(matched) {
+			matched = prefix
+		}
+	}
+	return matched, matched != ""
+}
@@ -0,0 +1,158 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+)
+
+// StatusPattern matches HTTP status codes. It is an exact code ("404"), an
+// inclusive range ("200-204"), a class ("2xx"), or any of these negated with a
+// leading "!" ("!404"). YAML integers decode as exact codes.
+type StatusPattern string
+
+// Validate reports whether the pattern is well formed
+func (p StatusPattern) Validate() error {
+	_, _, _, err := p.parse()
+	return err
+}
+
+// Negated reports whether the pattern excludes the codes it describes
+func (p StatusPattern) Negated() bool {
+	return strings.HasPrefix(strings.TrimSpace(string(p)), constants.StatusPatternNegation)
+}
+
+// Code returns the status code of an exact, non-negated pattern
+func (p StatusPattern) Code() (int, bool) {
+	low, high, negated, err := p.parse()
+	if err != nil || negated || low != high {
+		return 0, false
+	}
+	return low, true
+}
+
+// covers reports whether code lies within the pattern's codes, ignoring negation
+func (p StatusPattern) covers(code int) bool {
+	low, high, _, err := p.parse()
+	return err == nil && code >= low && code <= high
+}
+
+// parse returns the inclusive code range described by the pattern
+func (p StatusPattern) parse() (low int, high int, negated bool, err error) {
+	expr := strings.TrimSpace(string(p))
+	if rest, ok := strings.CutPrefix(expr, constants.StatusPatternNegation); ok {
+		negated = true
+		expr = strings.TrimSpace(rest)
+	}
+
+	invalid := fmt.Errorf(constants.StatusPatternInvalid, string(p))
+
+	switch {
+	case len(expr) == 3 && strings.EqualFold(expr[1:], constants.StatusClassSuffix):
+		class, convErr := strconv.Atoi(expr[:1])
+		if convErr != nil || class < 1 || class > 5 {
+			return 0, 0, false, invalid
+		}
+		return class * 100, class*100 + 99, negated, nil
+
+	case strings.Contains(expr, constants.StatusRangeSeparator):
+		lowStr, highStr, _ := strings.Cut(expr, constants.StatusRangeSeparator)
+		low, lowErr := parseStatusCode(lowStr)
+		high, highErr := parseStatusCode(highStr)
+		if lowErr != nil || highErr != nil || low > high {
+			return 0, 0, false, invalid
+		}
+		return low, high, negated, nil
+
+	default:
+		code, convErr := parseStatusCode(expr)
+		if convErr != nil {
+			return 0, 0, false, invalid
+		}
+		return code, code, negated, nil
+	}
+}
+
+// parseStatusCode parses a single HTTP status code in the range 100-599
+func parseStatusCode(value string) (int, error) {
+	code, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, err
+	}
+	if code < constants.MinHTTPStatusCode || code > constants.MaxHTTPStatusCode {
+		return 0, fmt.Errorf("status code %d out of range", code)
+	}
+	return code, nil
+}
+
+// StatusPatterns is a list of status patterns. A code matches when it matches
+// at least one positive pattern (or the list has only negated patterns) and no
+// negated pattern.
+type StatusPatterns []StatusPattern
+
+// StatusCodes builds a pattern list from exact status codes
+func StatusCodes(codes ...int) StatusPatterns {
+	patterns := make(StatusPatterns, 0, len(codes))
+	for _, code := range codes {
+		patterns = append(patterns, StatusPattern(strconv.Itoa(code)))
+	}
+	return patterns
+}
+
+// Matches reports whether the status code satisfies the pattern list
+func (s StatusPatterns) Matches(code int) bool {
+	if len(s) == 0 {
+		return false
+	}
+
+	hasPositive, matched := false, false
+	for _, pattern := range s {
+		if pattern.Negated() {
+			if pattern.covers(code) {
+				return false
+			}
+			continue
+		}
+		hasPositive = true
+		if pattern.covers(code) {
+			matched = true
+		}
+	}
+	return matched || !hasPositive
+}
+
+// Validate reports the first malformed pattern, naming the field it belongs to
+func (s StatusPatterns) Validate(field string) error {
+	for _, pattern := range s {
+		if err := pattern.Validate(); err != nil {
+			return fmt.Errorf(constants.StatusPatternFieldInvalid, field, err)
+		}
+	}
+	return nil
+}
+
+// SortedStatusPatterns orders status message keys with exact codes first, by
+// code, followed by ranges, classes and negations in lexical order. More
+// specific keys therefore take precedence when keys overlap.
+func SortedStatusPatterns[V any](messages map[StatusPattern]V) []StatusPattern {
+	keys := make([]StatusPattern, 0, len(messages))
+	for key := range messages {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ci, exactI := keys[i].Code()
+		cj, exactJ := keys[j].Code()
+		switch {
+		case exactI && exactJ:
+			return ci < cj
+		case exactI != exactJ:
+			return exactI
+		default:
+			return keys[i] < keys[j]
+		}
+	})
+	return keys
+}
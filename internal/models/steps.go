@@ -0,0 +1,78 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+)
+
+// Step is a preliminary request in a multi-step validation chain, for example
+// exchanging a key for a session token. Steps run in order before the main
+// request; values they extract become ${VAR} variables for later steps and
+// the main request.
+type Step struct {
+	Name            string                `yaml:"name" json:"name"`
+	APIURL          string                `yaml:"api_url" json:"api_url"`
+	Method          string                `yaml:"method,omitempty" json:"method,omitempty"`
+	Request         RequestConfig         `yaml:"request" json:"request"`
+	SuccessCriteria SuccessCriteria       `yaml:"success_criteria" json:"success_criteria"`
+	Extract         map[string]Extraction `yaml:"extract,omitempty" json:"extract,omitempty"` // Variable name (UPPER_SNAKE_CASE) to value source
+}
+
+// SetDefaults sets default values for the step, inheriting the template timeout
+func (s *Step) SetDefaults(parent *SecretTemplate) {
+	if s.Method == "" {
+		s.Method = constants.MethodGet
+	}
+	s.Method = strings.ToUpper(s.Method)
+	if s.Request.Timeout == 0 {
+		s.Request.Timeout = parent.Request.Timeout
+	}
+}
+
+// Validate validates the step definition
+func (s *Step) Validate() error {
+	if !constants.SnakeCaseNamePattern.MatchString(s.Name) {
+		return fmt.Errorf(constants.StepInvalidName, s.Name)
+	}
+	if s.APIURL == "" {
+		return fmt.Errorf(constants.StepURLRequired, s.Name)
+	}
+	if len(s.SuccessCriteria.StatusCode) == 0 {
+		return fmt.Errorf(constants.StepStatusCodeRequired, s.Name)
+	}
+	if err := s.Request.Validate(); err != nil {
+		return err
+	}
+	if err := s.SuccessCriteria.Validate(); err != nil {
+		return err
+	}
+	for name, extraction := range s.Extract {
+		if !constants.UpperSnakeCasePattern.MatchString(name) {
+			return fmt.Errorf(constants.StepVariableInvalidName, s.Name, name)
+		}
+		if err := extraction.validateSource(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Template returns a template describing the step request, so it can be executed
+// and checked like a main request. Error messages are inherited from the parent.
+func (s *Step) Template(parent *SecretTemplate) *SecretTemplate {
+	return &SecretTemplate{
+		Name:              parent.Name,
+		Mode:              parent.Mode,
+		OptionalVariables: parent.OptionalVariables,
+		Variables:         parent.Variables,
+		NoProxy:           parent.NoProxy,
+		TLS:               parent.TLS,
+		APIURL:            s.APIURL,
+		Method:            s.Method,
+		Request:           s.Request,
+		SuccessCriteria:   s.SuccessCriteria,
+		ErrorHandling:     parent.ErrorHandling,
+	}
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+)
+
+// TLSConfig customises certificate verification and client authentication,
+// either for every request (command-line flags) or for one template (tls:)
+type TLSConfig struct {
+	CAFiles            []string `yaml:"ca_files,omitempty" json:"ca_files,omitempty"`       // PEM bundles trusted in addition to the system roots
+	CertFile           string   `yaml:"cert_file,omitempty" json:"cert_file,omitempty"`     // PEM client certificate for mutual TLS
+	KeyFile            string   `yaml:"key_file,omitempty" json:"key_file,omitempty"`       // PEM private key, if not in cert_file
+	MinVersion         string   `yaml:"min_version,omitempty" json:"min_version,omitempty"` // 1.0, 1.1, 1.2 or 1.3
+	Pins               []string `yaml:"pins,omitempty" json:"pins,omitempty"`               // "sha256/<base64>" SPKI hashes; one must appear in the chain
+	InsecureSkipVerify bool     `yaml:"-" json:"insecure_skip_verify,omitempty"`            // Only set by --insecure-skip-verify, never by templates
+}
+
+// IsEmpty reports whether the config leaves the default TLS behaviour unchanged
+func (t *TLSConfig) IsEmpty() bool {
+	return t == nil || (len(t.CAFiles) == 0 && t.CertFile == "" && t.KeyFile == "" &&
+		t.MinVersion == "" && len(t.Pins) == 0 && !t.InsecureSkipVerify)
+}
+
+// Validate checks the settings that can be verified without reading files
+func (t *TLSConfig) Validate() error {
+	if t.MinVersion != "" {
+		if _, ok := constants.TLSVersions[t.MinVersion]; !ok {
+			return fmt.Errorf(constants.TLSMinVersionInvalid, t.MinVersion)
+		}
+	}
+	if t.KeyFile != "" && t.CertFile == "" {
+		return fmt.Errorf(constants.TLSKeyWithoutCert)
+	}
+	for _, pin := range t.Pins {
+		if _, err := ParsePin(pin); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Merge layers a template's settings over t. CA files accumulate, the
+// template's client certificate and pins replace t's, and the stricter
+// minimum version wins, with an unset version counting as TLS 1.2 so
+// templates can only raise it. Certificate verification can only be disabled
+// by t.
+func (t TLSConfig) Merge(template *TLSConfig) TLSConfig {
+	if template == nil {
+		return t
+	}
+	merged := t
+	merged.CAFiles = append(slices.Clone(t.CAFiles), template.CAFiles...)
+	if template.CertFile != "" {
+		merged.CertFile = template.CertFile
+		merged.KeyFile = template.KeyFile
+	}
+	floor := t.MinVersion
+	if floor == "" {
+		floor = constants.DefaultTLSMinVersion
+	}
+	if constants.TLSVersions[template.MinVersion] > constants.TLSVersions[floor] {
+		merged.MinVersion = template.MinVersion
+	}
+	if len(template.Pins) > 0 {
+		merged.Pins = template.Pins
+	}
+	return merged
+}
+
+// ParsePin decodes an SPKI pin, returning the SHA-256 hash it names. The
+// sha256/ prefix is optional.
+func ParsePin(pin string) ([]byte, error) {
+	hash, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(pin, constants.TLSPinPrefix))
+	if err != nil || len(hash) != sha256.Size {
+		return nil, fmt.Errorf(constants.TLSPinInvalid, pin)
+	}
+	return hash, nil
+}
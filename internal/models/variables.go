@@ -0,0 +1,135 @@
+package models
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+)
+
+// VariableSpec describes the expected shape of a template variable
+type VariableSpec struct {
+	Type        string `yaml:"type,omitempty" json:"type,omitempty"`               // url, hostname, uuid, email, integer or string (default)
+	Pattern     string `yaml:"pattern,omitempty" json:"pattern,omitempty"`         // Regex the value must match
+	Description string `yaml:"description,omitempty" json:"description,omitempty"` // Shown by archer info
+	Secret      *bool  `yaml:"secret,omitempty" json:"secret,omitempty"`           // Whether the value is sensitive
+}
+
+// Validate validates the variable specification
+func (s *VariableSpec) Validate(name string) error {
+	if s.Type != "" && !constants.VariableTypes[s.Type] {
+		return fmt.Errorf(constants.VariableSpecInvalidType, name, s.Type)
+	}
+	if s.Pattern != "" {
+		if _, err := regexp.Compile(s.Pattern); err != nil {
+			return fmt.Errorf(constants.VariableSpecInvalidPattern, name, err)
+		}
+	}
+	return nil
+}
+
+// Check verifies that a provided value satisfies the type and pattern constraints
+func (s *VariableSpec) Check(name string, value string) error {
+	switch s.Type {
+	case constants.VariableTypeURL:
+		parsed, err := url.Parse(value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf(constants.VariableInvalidURL, name)
+		}
+	case constants.VariableTypeHostname:
+		if len(value) > 253 || !constants.HostnamePattern.MatchString(value) {
+			return fmt.Errorf(constants.VariableInvalidType, name, s.Type)
+		}
+	case constants.VariableTypeUUID:
+		if !constants.UUIDPattern.MatchString(value) {
+			return fmt.Errorf(constants.VariableInvalidType, name, s.Type)
+		}
+	case constants.VariableTypeEmail:
+		address, err := mail.ParseAddress(value)
+		if err != nil || address.Address != value {
+			return fmt.Errorf(constants.VariableInvalidType, name, s.Type)
+		}
+	case constants.VariableTypeInteger:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf(constants.VariableInvalidType, name, s.Type)
+		}
+	}
+
+	if s.Pattern != "" && !regexp.MustCompile(s.Pattern).MatchString(value) {
+		return fmt.Errorf(constants.VariablePatternMismatch, name, s.Pattern)
+	}
+	return nil
+}
+
+// Constraints returns a short description of the type and pattern constraints
+func (s *VariableSpec) Constraints() string {
+	parts := make([]string, 0, 2)
+	if s.Type != "" && s.Type != constants.VariableTypeString {
+		parts = append(parts, "type: "+s.Type)
+	}
+	if s.Pattern != "" {
+		parts = append(parts, "pattern: "+s.Pattern)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// IsSecretVariable reports whether a variable holds sensitive data. An explicit
+// secret flag wins; otherwise ${SECRET} and required variables are treated as
+// secret and optional variables as non-secret.
+func (t *SecretTemplate) IsSecretVariable(name string) bool {
+	if spec, ok := t.Variables[name]; ok && spec.Secret != nil {
+		return *spec.Secret
+	}
+	if _, optional := t.OptionalVariables[name]; optional {
+		return false
+	}
+	return true
+}
+
+// CheckVariables verifies provided variable values against their specifications,
+// returning one error per invalid variable in name order
+func (t *SecretTemplate) CheckVariables(vars map[string]string) []error {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		spec, ok := t.Variables[name]
+		if !ok {
+			continue
+		}
+		if err := spec.Check(name, vars[name]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// validateVariableSpecs checks that every spec refers to a declared variable and is well-formed,
+// and that optional variable defaults satisfy their spec
+func (t *SecretTemplate) validateVariableSpecs() error {
+	for name, spec := range t.Variables {
+		defaultValue, optional := t.OptionalVariables[name]
+		if name != constants.SecretVariableName && !optional && !slices.Contains(t.RequiredVariables, name) {
+			return fmt.Errorf(constants.VariableSpecUndeclared, name)
+		}
+		if err := spec.Validate(name); err != nil {
+			return err
+		}
+		if strings.TrimSpace(defaultValue) != "" {
+			if err := spec.Check(name, defaultValue); err != nil {
+				return fmt.Errorf(constants.OptionalDefaultInvalid, name, err)
+			}
+		}
+	}
+	return nil
+}
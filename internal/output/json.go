@@ -9,6 +9,15 @@ import (
 
 // WriteJSONFile writes ValidationResultJSON to a file with pretty formatting
 func WriteJSONFile(filepath string, result *models.ValidationResultJSON) error {
+	return writeIndented(filepath, result)
+}
+
+// WriteInspectJSONFile writes InspectResultJSON to a file with pretty formatting
+func WriteInspectJSONFile(filepath string, result *models.InspectResultJSON) error {
+	return writeIndented(filepath, result)
+}
+
+func writeIndented(filepath string, result any) error {
 	// Marshal with indentation for readability
 	data, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
@@ -0,0 +1,23 @@
+package templates
+
+import (
+	"io/fs"
+
+	"github.com/theinfosecguy/archer"
+	"github.com/theinfosecguy/archer/internal/constants"
+)
+
+// builtinTemplatesFS is created once so describeFS can recognise it
+var builtinTemplatesFS = func() fs.FS {
+	sub, err := fs.Sub(archer.BuiltinTemplates, constants.BuiltinTemplatesDir)
+	if err != nil {
+		// The embed directive guarantees the directory exists
+		panic(err)
+	}
+	return sub
+}()
+
+// BuiltinTemplatesFS returns the built-in templates embedded in the binary
+func BuiltinTemplatesFS() fs.FS {
+	return builtinTemplatesFS
+}
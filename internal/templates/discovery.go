@@ -1,7 +1,8 @@
 package templates
 
 import (
-	"os"
+	"io/fs"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -10,41 +11,37 @@ import (
 	"github.com/theinfosecguy/archer/internal/errors"
 )
 
-// DiscoverTemplatesInDirectory discovers all available template files in a directory
-func DiscoverTemplatesInDirectory(templatesDir string) ([]string, error) {
+// DiscoverTemplatesInDirectory discovers all available template files in a templates filesystem
+func DiscoverTemplatesInDirectory(templatesFS fs.FS) ([]string, error) {
 	// Check if directory exists
-	info, err := os.Stat(templatesDir)
-	if os.IsNotExist(err) {
+	info, err := fs.Stat(templatesFS, ".")
+	if err != nil || !info.IsDir() {
 		return nil, &errors.TemplateDirectoryNotFoundError{
-			Directory: templatesDir,
-		}
-	}
-
-	if !info.IsDir() {
-		return nil, &errors.TemplateDirectoryNotFoundError{
-			Directory: templatesDir,
+			Directory: describeFS(templatesFS),
 		}
 	}
 
 	templateNames := make(map[string]bool)
 
 	// Walk through directory
-	err = filepath.Walk(templatesDir, func(path string, info os.FileInfo, err error) error {
+	err = fs.WalkDir(templatesFS, ".", func(filePath string, entry fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
 		// Skip directories
-		if info.IsDir() {
+		if entry.IsDir() {
 			return nil
 		}
 
 		// Check if file has valid extension
-		ext := filepath.Ext(path)
+		ext := path.Ext(filePath)
 		if ext == constants.TemplateFileExtension || ext == constants.TemplateFileExtension2 {
-			// Get filename without extension
-			name := strings.TrimSuffix(filepath.Base(path), ext)
-			templateNames[name] = true
+			// Get filename without extension, skipping partial bases used only by extends
+			name := strings.TrimSuffix(path.Base(filePath), ext)
+			if !strings.HasPrefix(name, constants.PartialTemplatePrefix) {
+				templateNames[name] = true
+			}
 		}
 
 		return nil
@@ -64,13 +61,14 @@ func DiscoverTemplatesInDirectory(templatesDir string) ([]string, error) {
 	return names, nil
 }
 
-// DiscoverTemplates discovers templates from the default templates directory
-func DiscoverTemplates(templatesDir string) []string {
-	if templatesDir == "" {
-		templatesDir = constants.DefaultTemplatesDir
+// DiscoverTemplates discovers templates from a templates filesystem, treating
+// a nil filesystem as NewTemplateLoader does
+func DiscoverTemplates(templatesFS fs.FS) []string {
+	if templatesFS == nil {
+		templatesFS = BuiltinTemplatesFS()
 	}
 
-	names, err := DiscoverTemplatesInDirectory(templatesDir)
+	names, err := DiscoverTemplatesInDirectory(templatesFS)
 	if err != nil {
 		return []string{}
 	}
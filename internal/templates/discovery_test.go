@@ -7,9 +7,9 @@ import (
 )
 
 func TestDiscoverTemplatesInDirectory_ValidDirectory(t *testing.T) {
-	templatesDir := "testdata"
+	templatesFS := os.DirFS("testdata")
 
-	names, err := DiscoverTemplatesInDirectory(templatesDir)
+	names, err := DiscoverTemplatesInDirectory(templatesFS)
 
 	if err != nil {
 		t.Fatalf("DiscoverTemplatesInDirectory() error = %v, want nil", err)
@@ -35,9 +35,9 @@ func TestDiscoverTemplatesInDirectory_ValidDirectory(t *testing.T) {
 }
 
 func TestDiscoverTemplatesInDirectory_DirectoryNotFound(t *testing.T) {
-	templatesDir := "nonexistent_directory"
+	templatesFS := os.DirFS("nonexistent_directory")
 
-	names, err := DiscoverTemplatesInDirectory(templatesDir)
+	names, err := DiscoverTemplatesInDirectory(templatesFS)
 
 	if err == nil {
 		t.Fatal("DiscoverTemplatesInDirectory() error = nil, want directory not found error")
@@ -51,7 +51,7 @@ func TestDiscoverTemplatesInDirectory_DirectoryNotFound(t *testing.T) {
 func TestDiscoverTemplatesInDirectory_EmptyDirectory(t *testing.T) {
 	tempDir := t.TempDir()
 
-	names, err := DiscoverTemplatesInDirectory(tempDir)
+	names, err := DiscoverTemplatesInDirectory(os.DirFS(tempDir))
 
 	if err != nil {
 		t.Fatalf("DiscoverTemplatesInDirectory() error = %v, want nil", err)
@@ -94,7 +94,7 @@ error_handling:
 		t.Fatalf("Failed to create .yml file: %v", err)
 	}
 
-	names, err := DiscoverTemplatesInDirectory(tempDir)
+	names, err := DiscoverTemplatesInDirectory(os.DirFS(tempDir))
 
 	if err != nil {
 		t.Fatalf("DiscoverTemplatesInDirectory() error = %v, want nil", err)
@@ -153,7 +153,7 @@ error_handling:
 		t.Fatalf("Failed to create config.json: %v", err)
 	}
 
-	names, err := DiscoverTemplatesInDirectory(tempDir)
+	names, err := DiscoverTemplatesInDirectory(os.DirFS(tempDir))
 
 	if err != nil {
 		t.Fatalf("DiscoverTemplatesInDirectory() error = %v, want nil", err)
@@ -198,7 +198,7 @@ error_handling:
 		}
 	}
 
-	names, err := DiscoverTemplatesInDirectory(tempDir)
+	names, err := DiscoverTemplatesInDirectory(os.DirFS(tempDir))
 
 	if err != nil {
 		t.Fatalf("DiscoverTemplatesInDirectory() error = %v, want nil", err)
@@ -249,7 +249,7 @@ error_handling:
 		t.Fatalf("Failed to create subdirectory: %v", err)
 	}
 
-	names, err := DiscoverTemplatesInDirectory(tempDir)
+	names, err := DiscoverTemplatesInDirectory(os.DirFS(tempDir))
 
 	if err != nil {
 		t.Fatalf("DiscoverTemplatesInDirectory() error = %v, want nil", err)
@@ -265,27 +265,38 @@ error_handling:
 }
 
 func TestDiscoverTemplates_ValidDirectory(t *testing.T) {
-	templatesDir := "testdata"
+	templatesFS := os.DirFS("testdata")
 
-	names := DiscoverTemplates(templatesDir)
+	names := DiscoverTemplates(templatesFS)
 
 	if len(names) == 0 {
 		t.Error("No templates discovered, want at least one")
 	}
 }
 
-func TestDiscoverTemplates_EmptyString(t *testing.T) {
-	names := DiscoverTemplates("")
+func TestDiscoverTemplates_NilFallsBackToBuiltin(t *testing.T) {
+	names := DiscoverTemplates(nil)
 
-	if names == nil {
-		t.Error("DiscoverTemplates('') returned nil, want empty slice")
+	if len(names) == 0 {
+		t.Fatal("DiscoverTemplates(nil) returned no templates, want built-in templates")
+	}
+
+	found := false
+	for _, name := range names {
+		if name == "github" {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Errorf("Built-in templates %v do not include 'github'", names)
 	}
 }
 
 func TestDiscoverTemplates_DirectoryNotFound(t *testing.T) {
-	templatesDir := "nonexistent_directory"
+	templatesFS := os.DirFS("nonexistent_directory")
 
-	names := DiscoverTemplates(templatesDir)
+	names := DiscoverTemplates(templatesFS)
 
 	if len(names) != 0 {
 		t.Errorf("Discovered %d templates from nonexistent directory, want 0", len(names))
@@ -384,7 +395,7 @@ error_handling:
 		t.Fatalf("Failed to create gitlab.yml: %v", err)
 	}
 
-	names, err := DiscoverTemplatesInDirectory(tempDir)
+	names, err := DiscoverTemplatesInDirectory(os.DirFS(tempDir))
 
 	if err != nil {
 		t.Fatalf("DiscoverTemplatesInDirectory() error = %v, want nil", err)
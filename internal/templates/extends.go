@@ -0,0 +1,264 @@
+package templates
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+	"github.com/theinfosecguy/archer/internal/errors"
+)
+
+// templateDocument is a template file together with the place it was loaded from,
+// which determines how the names and paths in its extends key are resolved
+type templateDocument struct {
+	fsys        fs.FS  // Templates filesystem, nil for files on the local disk
+	path        string // Path within fsys, or on the local disk
+	sourceIndex int    // Search path layer the document belongs to, -1 outside the search path
+}
+
+// key identifies the document for cycle detection
+func (d templateDocument) key() string {
+	if d.fsys == nil {
+		if absPath, err := filepath.Abs(d.path); err == nil {
+			return absPath
+		}
+		return d.path
+	}
+	return describeFS(d.fsys) + ":" + d.path
+}
+
+// name returns the template name derived from the file name
+func (d templateDocument) name() string {
+	base := path.Base(filepath.ToSlash(d.path))
+	return strings.TrimSuffix(base, path.Ext(base))
+}
+
+// read returns the raw document contents
+func (d templateDocument) read() ([]byte, error) {
+	var data []byte
+	var err error
+	if d.fsys == nil {
+		data, err = os.ReadFile(d.path)
+	} else {
+		data, err = fs.ReadFile(d.fsys, d.path)
+	}
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, &errors.TemplateNotFoundError{TemplateName: d.path}
+		}
+		return nil, &errors.TemplateLoadError{TemplateName: d.path, Cause: err}
+	}
+	return data, nil
+}
+
+// loadNode parses a document and, if it extends another template, deep-merges it
+// over its parent. chain holds the documents already being loaded, for cycle detection.
+func (l *TemplateLoader) loadNode(doc templateDocument, chain []templateDocument) (*yaml.Node, error) {
+	for i, loading := range chain {
+		if loading.key() == doc.key() {
+			return nil, &errors.TemplateValidationError{
+				TemplateName: chain[0].path,
+				Message:      fmt.Sprintf(constants.ExtendsCycle, describeChain(append(chain[i:], doc))),
+			}
+		}
+	}
+
+	data, err := doc.read()
+	if err != nil {
+		return nil, err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, &errors.TemplateLoadError{
+			TemplateName: doc.path,
+			Cause:        fmt.Errorf("YAML parsing failed: %w", err),
+		}
+	}
+
+	root := &node
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+
+	resolveTLSPaths(root, l.documentDir(doc))
+
+	extends := mappingValue(root, constants.ExtendsKey)
+	if extends == nil {
+		return root, nil
+	}
+	if extends.Kind != yaml.ScalarNode || extends.Value == "" {
+		return nil, &errors.TemplateValidationError{
+			TemplateName: doc.path,
+			Message:      constants.ExtendsInvalid,
+		}
+	}
+
+	parentDoc, err := l.resolveParent(doc, extends.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	parent, err := l.loadNode(parentDoc, append(chain, doc))
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeNodes(parent, root), nil
+}
+
+// resolveParent locates the template named by an extends key. File paths are
+// relative to the extending document. Names are looked up on the search path;
+// a template extending its own name resolves to the next lower layer, so an
+// override can extend the template it shadows.
+func (l *TemplateLoader) resolveParent(doc templateDocument, ref string) (templateDocument, error) {
+	if IsFilePath(ref) {
+		if doc.fsys == nil {
+			parentPath := ref
+			if !filepath.IsAbs(parentPath) {
+				parentPath = filepath.Join(filepath.Dir(doc.path), parentPath)
+			}
+			return templateDocument{path: parentPath, sourceIndex: -1}, nil
+		}
+		return templateDocument{
+			fsys:        doc.fsys,
+			path:        path.Join(path.Dir(doc.path), filepath.ToSlash(ref)),
+			sourceIndex: doc.sourceIndex,
+		}, nil
+	}
+
+	start := len(l.Sources) - 1
+	if doc.sourceIndex >= 0 && ref == doc.name() {
+		start = doc.sourceIndex - 1
+	}
+	for i := start; i >= 0; i-- {
+		if templatePath, ok := findDocumentFile(l.Sources[i].FS, ref); ok {
+			return templateDocument{fsys: l.Sources[i].FS, path: templatePath, sourceIndex: i}, nil
+		}
+	}
+
+	return templateDocument{}, &errors.TemplateValidationError{
+		TemplateName: doc.path,
+		Message:      fmt.Sprintf(constants.ExtendsParentNotFound, ref),
+	}
+}
+
+// documentDir returns the directory on disk holding a document, or an empty
+// string for documents that are not files on disk, such as built-in templates
+func (l *TemplateLoader) documentDir(doc templateDocument) string {
+	if doc.fsys == nil {
+		return filepath.Dir(doc.path)
+	}
+	if doc.sourceIndex < 0 || doc.sourceIndex >= len(l.Sources) || l.Sources[doc.sourceIndex].Path == "" {
+		return ""
+	}
+	root := l.Sources[doc.sourceIndex].Path
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return ""
+	}
+	return filepath.Join(root, filepath.FromSlash(path.Dir(doc.path)))
+}
+
+// resolveTLSPaths makes relative certificate paths in a document's tls block
+// relative to dir, the directory holding the document, rather than to the
+// working directory
+func resolveTLSPaths(root *yaml.Node, dir string) {
+	tlsNode := mappingValue(root, constants.TLSKey)
+	if tlsNode == nil || dir == "" {
+		return
+	}
+
+	resolve := func(node *yaml.Node) {
+		if node != nil && node.Kind == yaml.ScalarNode && node.Value != "" && !filepath.IsAbs(node.Value) {
+			node.Value = filepath.Join(dir, filepath.FromSlash(node.Value))
+		}
+	}
+	resolve(mappingValue(tlsNode, constants.TLSCertFileKey))
+	resolve(mappingValue(tlsNode, constants.TLSKeyFileKey))
+	if caFiles := mappingValue(tlsNode, constants.TLSCAFilesKey); caFiles != nil && caFiles.Kind == yaml.SequenceNode {
+		for _, item := range caFiles.Content {
+			resolve(item)
+		}
+	}
+}
+
+// findTemplateFile returns the file for a template name, trying .yaml before .yml.
+// Partials such as _base are only found through extends, by findDocumentFile.
+func findTemplateFile(templatesFS fs.FS, templateName string) (string, bool) {
+	if strings.HasPrefix(templateName, constants.PartialTemplatePrefix) {
+		return "", false
+	}
+	return findDocumentFile(templatesFS, templateName)
+}
+
+// findDocumentFile returns the file for a template or partial name, trying
+// .yaml before .yml
+func findDocumentFile(templatesFS fs.FS, templateName string) (string, bool) {
+	if templatesFS == nil {
+		return "", false
+	}
+	for _, ext := range []string{constants.TemplateFileExtension, constants.TemplateFileExtension2} {
+		if _, err := fs.Stat(templatesFS, templateName+ext); err == nil {
+			return templateName + ext, true
+		}
+	}
+	return "", false
+}
+
+// mergeNodes deep-merges child over parent. Mappings (headers, query params,
+// error messages, success criteria, ...) are merged key by key; scalars and
+// sequences in the child replace the parent's value.
+func mergeNodes(parent, child *yaml.Node) *yaml.Node {
+	if parent.Kind != yaml.MappingNode || child.Kind != yaml.MappingNode {
+		return child
+	}
+
+	merged := &yaml.Node{Kind: yaml.MappingNode, Tag: child.Tag}
+	merged.Content = append(merged.Content, parent.Content...)
+
+	for i := 0; i+1 < len(child.Content); i += 2 {
+		key, value := child.Content[i], child.Content[i+1]
+		replaced := false
+		for j := 0; j+1 < len(merged.Content); j += 2 {
+			if merged.Content[j].Value == key.Value {
+				merged.Content[j+1] = mergeNodes(merged.Content[j+1], value)
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged.Content = append(merged.Content, key, value)
+		}
+	}
+
+	return merged
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// describeChain formats an extends chain as "a -> b -> a"
+func describeChain(chain []templateDocument) string {
+	paths := make([]string, 0, len(chain))
+	for _, doc := range chain {
+		paths = append(paths, doc.path)
+	}
+	return strings.Join(paths, " -> ")
+}
@@ -0,0 +1,333 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+	"github.com/theinfosecguy/archer/internal/models"
+)
+
+func writeTemplateFile(t *testing.T, dir string, fileName string, content string) string {
+	t.Helper()
+	filePath := filepath.Join(dir, fileName)
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", fileName, err)
+	}
+	return filePath
+}
+
+func TestLoadTemplateFromFile_ExtendsFileDeepMerges(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTemplateFile(t, tempDir, "_base.yaml", `request:
+  headers:
+    User-Agent: archer/1.0
+    Accept: application/json
+  query_params:
+    format: json
+success_criteria:
+  status_code: [200]
+error_handling:
+  max_retries: 2
+  retry_delay: 1
+  error_messages:
+    401: Invalid token
+    429: Rate limit exceeded
+`)
+	childPath := writeTemplateFile(t, tempDir, "service.yaml", `name: service
+description: Service token validation
+extends: _base.yaml
+api_url: https://api.example.com/me
+request:
+  headers:
+    Authorization: Bearer ${SECRET}
+    Accept: application/vnd.example+json
+success_criteria:
+  required_fields: [id]
+error_handling:
+  max_retries: 0
+  error_messages:
+    401: Invalid service token
+`)
+
+	template, err := LoadTemplateFromFile(childPath)
+	if err != nil {
+		t.Fatalf("LoadTemplateFromFile() error = %v, want nil", err)
+	}
+
+	expectedHeaders := map[string]string{
+		"User-Agent":    "archer/1.0",
+		"Accept":        "application/vnd.example+json",
+		"Authorization": "Bearer ${SECRET}",
+	}
+	for key, want := range expectedHeaders {
+		if got := template.Request.Headers[key]; got != want {
+			t.Errorf("Headers[%q] = %q, want %q", key, got, want)
+		}
+	}
+
+	if template.Request.QueryParams["format"] != "json" {
+		t.Errorf("QueryParams = %v, want inherited format=json", template.Request.QueryParams)
+	}
+
+	if len(template.SuccessCriteria.StatusCode) != 1 || template.SuccessCriteria.StatusCode[0] != "200" {
+		t.Errorf("StatusCode = %v, want inherited [200]", template.SuccessCriteria.StatusCode)
+	}
+
+	if len(template.SuccessCriteria.RequiredFields) != 1 || template.SuccessCriteria.RequiredFields[0] != "id" {
+		t.Errorf("RequiredFields = %v, want [id]", template.SuccessCriteria.RequiredFields)
+	}
+
+	if template.ErrorHandling.MaxRetries != 0 || template.ErrorHandling.RetryDelay != models.Seconds(1) {
+		t.Errorf("ErrorHandling = %d retries / %s, want explicit 0 retries and inherited 1s", template.ErrorHandling.MaxRetries, template.ErrorHandling.RetryDelay)
+	}
+
+	if template.ErrorHandling.ErrorMessages["401"] != "Invalid service token" || template.ErrorHandling.ErrorMessages["429"] != "Rate limit exceeded" {
+		t.Errorf("ErrorMessages = %v, want overridden 401 and inherited 429", template.ErrorHandling.ErrorMessages)
+	}
+
+	if template.Extends != "_base.yaml" {
+		t.Errorf("Extends = %q, want '_base.yaml'", template.Extends)
+	}
+}
+
+func TestResolveTemplate_ExtendsBuiltinByName(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTemplateFile(t, tempDir, "github-enterprise.yaml", `name: github-enterprise
+description: GitHub Enterprise Server token validation
+extends: github
+api_url: https://github.example.com/api/v3/user
+`)
+
+	loader := NewLayeredTemplateLoader(BuiltinSource(), DirectorySource("user", tempDir))
+
+	template, _, err := loader.ResolveTemplate("github-enterprise")
+	if err != nil {
+		t.Fatalf("ResolveTemplate() error = %v, want nil", err)
+	}
+
+	if template.APIURL != "https://github.example.com/api/v3/user" {
+		t.Errorf("APIURL = %q, want enterprise URL", template.APIURL)
+	}
+
+	if template.Request.Headers["Authorization"] != "Bearer ${SECRET}" {
+		t.Errorf("Authorization header = %q, want inherited from github", template.Request.Headers["Authorization"])
+	}
+
+	if template.ErrorHandling.MaxRetries != 2 {
+		t.Errorf("MaxRetries = %d, want 2 inherited through github from the shared base", template.ErrorHandling.MaxRetries)
+	}
+}
+
+func TestResolveTemplate_OverrideExtendsShadowedTemplate(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTemplateFile(t, tempDir, "github.yaml", `extends: github
+request:
+  timeout: 30
+`)
+
+	loader := NewLayeredTemplateLoader(BuiltinSource(), DirectorySource("user", tempDir))
+
+	template, source, err := loader.ResolveTemplate("github")
+	if err != nil {
+		t.Fatalf("ResolveTemplate() error = %v, want nil", err)
+	}
+
+	if source.Name != "user" {
+		t.Errorf("source.Name = %q, want 'user'", source.Name)
+	}
+
+	if template.Request.Timeout != 30 {
+		t.Errorf("Timeout = %d, want overridden 30", template.Request.Timeout)
+	}
+
+	if template.APIURL != "https://api.github.com/user" {
+		t.Errorf("APIURL = %q, want inherited built-in URL", template.APIURL)
+	}
+}
+
+func TestLoadTemplateFromFile_ExtendsCycle(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTemplateFile(t, tempDir, "a.yaml", "name: a\nextends: b.yaml\napi_url: https://a\n")
+	writeTemplateFile(t, tempDir, "b.yaml", "name: b\nextends: a.yaml\napi_url: https://b\n")
+
+	template, err := LoadTemplateFromFile(filepath.Join(tempDir, "a.yaml"))
+
+	if err == nil {
+		t.Fatal("LoadTemplateFromFile() error = nil, want cycle error")
+	}
+
+	if !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("error = %v, want cycle error", err)
+	}
+
+	if template != nil {
+		t.Errorf("template = %v, want nil", template)
+	}
+}
+
+func TestLoadTemplateFromFile_ExtendsParentNotFound(t *testing.T) {
+	tempDir := t.TempDir()
+	childPath := writeTemplateFile(t, tempDir, "child.yaml", "name: child\nextends: does-not-exist\napi_url: https://a\n")
+
+	_, err := LoadTemplateFromFile(childPath)
+
+	if err == nil || !strings.Contains(err.Error(), "does-not-exist") {
+		t.Errorf("LoadTemplateFromFile() error = %v, want parent not found error", err)
+	}
+}
+
+func TestDiscoverTemplatesInDirectory_SkipsPartials(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTemplateFile(t, tempDir, "_base.yaml", "request:\n  timeout: 10\n")
+	writeTemplateFile(t, tempDir, "service.yaml", "name: service\nextends: _base.yaml\napi_url: https://a\n")
+
+	names, err := DiscoverTemplatesInDirectory(os.DirFS(tempDir))
+	if err != nil {
+		t.Fatalf("DiscoverTemplatesInDirectory() error = %v, want nil", err)
+	}
+
+	if len(names) != 1 || names[0] != "service" {
+		t.Errorf("names = %v, want [service]", names)
+	}
+}
+
+func TestResolveTemplate_RejectsPartials(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTemplateFile(t, tempDir, "_base.yaml", "request:\n  timeout: 10\n")
+	writeTemplateFile(t, tempDir, "service.yaml", `name: service
+extends: _base
+api_url: https://api.example.com/me
+request:
+  headers:
+    Authorization: Bearer ${SECRET}
+success_criteria:
+  status_code: [200]
+`)
+	loader := NewLayeredTemplateLoader(DirectorySource(constants.SourceDirectory, tempDir))
+
+	if loader.Sources[0].HasTemplate("_base") {
+		t.Error("HasTemplate(_base) = true, want partials hidden")
+	}
+	if _, _, err := loader.ResolveTemplate("_base"); err == nil {
+		t.Error("ResolveTemplate(_base) error = nil, want not found")
+	}
+
+	template, err := loader.GetTemplate("service")
+	if err != nil {
+		t.Fatalf("GetTemplate(service) error = %v, want nil", err)
+	}
+	if template.Request.Timeout != 10 {
+		t.Errorf("Timeout = %d, want 10 from _base", template.Request.Timeout)
+	}
+}
+
+func TestLoadTemplateFromFile_PartialWithoutAPIURL(t *testing.T) {
+	basePath := writeTemplateFile(t, t.TempDir(), "_base.yaml", "request:\n  timeout: 10\n")
+
+	if _, err := LoadTemplateFromFile(basePath); err == nil || !strings.Contains(err.Error(), "api_url") {
+		t.Errorf("LoadTemplateFromFile(_base.yaml) error = %v, want api_url required", err)
+	}
+}
+
+func TestBuiltinTemplates_InheritSharedBase(t *testing.T) {
+	template, err := LoadTemplateFromDirectory("npm", BuiltinTemplatesFS())
+	if err != nil {
+		t.Fatalf("LoadTemplateFromDirectory() error = %v, want nil", err)
+	}
+
+	if template.Request.Headers["User-Agent"] != "archer/1.0" {
+		t.Errorf("User-Agent = %q, want 'archer/1.0' from _base.yaml", template.Request.Headers["User-Agent"])
+	}
+
+	if template.ErrorHandling.MaxRetries != 2 || template.ErrorHandling.RetryDelay.Duration() != time.Second {
+		t.Errorf("ErrorHandling = %+v, want shared base defaults", template.ErrorHandling)
+	}
+}
+
+func TestBuiltinTemplates_BaseLeavesMessagesToTemplates(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		message   string
+	}{
+		{"github", "archer/1.0", ""},
+		{"airtable", "archer/1.0", ""},
+		{"slack", "archer/1.0", "Rate limit exceeded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, err := LoadTemplateFromDirectory(tt.name, BuiltinTemplatesFS())
+			if err != nil {
+				t.Fatalf("LoadTemplateFromDirectory() error = %v, want nil", err)
+			}
+			if got := template.Request.Headers["User-Agent"]; got != tt.userAgent {
+				t.Errorf("User-Agent = %q, want %q", got, tt.userAgent)
+			}
+			if got := template.ErrorHandling.ErrorMessages["429"]; got != tt.message {
+				t.Errorf("429 message = %q, want %q", got, tt.message)
+			}
+		})
+	}
+}
+
+func TestLoadTemplate_TLSPathsRelativeToTemplate(t *testing.T) {
+	baseDir := t.TempDir()
+	writeTemplateFile(t, baseDir, "_internal.yaml", `tls:
+  ca_files: [certs/corp-ca.pem, /etc/ssl/system-ca.pem]
+`)
+	childDir := t.TempDir()
+	childPath := writeTemplateFile(t, childDir, "ghe.yaml", `name: ghe
+description: GitHub Enterprise
+extends: `+filepath.Join(baseDir, "_internal.yaml")+`
+api_url: https://ghe.internal.example/api/v3/user
+request:
+  headers:
+    Authorization: token ${SECRET}
+success_criteria:
+  status_code: [200]
+tls:
+  cert_file: archer.pem
+  key_file: keys/archer.key
+`)
+
+	template, err := LoadTemplateFromFile(childPath)
+	if err != nil {
+		t.Fatalf("LoadTemplateFromFile() error = %v, want nil", err)
+	}
+
+	want := models.TLSConfig{
+		CAFiles:  []string{filepath.Join(baseDir, "certs", "corp-ca.pem"), "/etc/ssl/system-ca.pem"},
+		CertFile: filepath.Join(childDir, "archer.pem"),
+		KeyFile:  filepath.Join(childDir, "keys", "archer.key"),
+	}
+	if template.TLS == nil || !reflect.DeepEqual(*template.TLS, want) {
+		t.Errorf("TLS = %+v, want %+v", template.TLS, want)
+	}
+
+	userDir := t.TempDir()
+	writeTemplateFile(t, userDir, "gitlab-internal.yaml", `name: gitlab-internal
+description: Self-hosted GitLab
+api_url: https://gitlab.internal.example/api/v4/user
+request:
+  headers:
+    PRIVATE-TOKEN: ${SECRET}
+success_criteria:
+  status_code: [200]
+tls:
+  cert_file: archer.pem
+`)
+	loader := NewLayeredTemplateLoader(BuiltinSource(), DirectorySource("user", userDir))
+	resolved, _, err := loader.ResolveTemplate("gitlab-internal")
+	if err != nil {
+		t.Fatalf("ResolveTemplate() error = %v, want nil", err)
+	}
+	if resolved.TLS.CertFile != filepath.Join(userDir, "archer.pem") {
+		t.Errorf("CertFile = %q, want it relative to the templates directory", resolved.TLS.CertFile)
+	}
+}
@@ -0,0 +1,53 @@
+package templates
+
+import (
+	"sort"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+	"github.com/theinfosecguy/archer/internal/logger"
+	"github.com/theinfosecguy/archer/internal/models"
+)
+
+// Candidate is a template whose secret_format accepts a secret
+type Candidate struct {
+	Name     string                 // Template name on the search path
+	Source   TemplateSource         // Layer the template resolved from
+	Score    int                    // How specifically the template's format matches the secret
+	Template *models.SecretTemplate // Loaded template
+}
+
+// IdentifyTemplates ranks the single-mode templates on the search path whose
+// secret_format accepts the secret, most specific first. Templates without a
+// secret_format accept anything and are never candidates.
+func (l *TemplateLoader) IdentifyTemplates(secret string) []Candidate {
+	candidates := make([]Candidate, 0)
+
+	for _, entry := range l.ListTemplates() {
+		template, err := l.GetTemplate(entry.Name)
+		if err != nil {
+			logger.Debug("Skipping template '%s' during identification: %v", entry.Name, err)
+			continue
+		}
+		if template.Mode != constants.ModeSingle || template.SecretFormat == nil {
+			continue
+		}
+		if template.SecretFormat.Check(secret) != nil {
+			continue
+		}
+		candidates = append(candidates, Candidate{
+			Name:     entry.Name,
+			Source:   entry.Source,
+			Score:    template.SecretFormat.Specificity(secret),
+			Template: template,
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+
+	return candidates
+}
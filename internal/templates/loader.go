@@ -2,29 +2,37 @@ package templates
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
+	"io/fs"
+	"reflect"
 	"strings"
 
-	"gopkg.in/yaml.v3"
-
 	"github.com/theinfosecguy/archer/internal/constants"
 	"github.com/theinfosecguy/archer/internal/errors"
 	"github.com/theinfosecguy/archer/internal/models"
 )
 
-// TemplateLoader loads templates from default directory or individual files
+// TemplateLoader loads templates from an ordered search path or individual files
 type TemplateLoader struct {
-	DefaultTemplatesDir string
+	Sources []TemplateSource // Search path layers; later layers override earlier ones by name
 }
 
-// NewTemplateLoader creates a new template loader
-func NewTemplateLoader(defaultTemplatesDir string) *TemplateLoader {
-	if defaultTemplatesDir == "" {
-		defaultTemplatesDir = constants.DefaultTemplatesDir
+// NewTemplateLoader creates a new template loader backed by a single templates filesystem.
+// A nil filesystem falls back to the built-in templates embedded in the binary.
+func NewTemplateLoader(templatesFS fs.FS) *TemplateLoader {
+	if templatesFS == nil {
+		return NewLayeredTemplateLoader(BuiltinSource())
 	}
+	return NewLayeredTemplateLoader(TemplateSource{
+		Name: constants.SourceDirectory,
+		Path: describeFS(templatesFS),
+		FS:   templatesFS,
+	})
+}
+
+// NewLayeredTemplateLoader creates a template loader from an ordered list of sources
+func NewLayeredTemplateLoader(sources ...TemplateSource) *TemplateLoader {
 	return &TemplateLoader{
-		DefaultTemplatesDir: defaultTemplatesDir,
+		Sources: sources,
 	}
 }
 
@@ -36,29 +44,55 @@ func IsFilePath(identifier string) bool {
 		strings.HasSuffix(identifier, ".yml")
 }
 
-// LoadTemplateFromFile loads a template from a specific file path
+// LoadTemplateFromFile loads a template from a specific file path.
+// Templates named in its extends key are resolved against the built-in templates.
 func LoadTemplateFromFile(filePath string) (*models.SecretTemplate, error) {
-	// Check if file exists
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+	return NewTemplateLoader(nil).loadTemplate(templateDocument{path: filePath, sourceIndex: -1})
+}
+
+// LoadTemplateFromFS loads a template from a file inside a templates filesystem
+func LoadTemplateFromFS(templatesFS fs.FS, filePath string) (*models.SecretTemplate, error) {
+	return NewTemplateLoader(templatesFS).loadTemplate(templateDocument{fsys: templatesFS, path: filePath, sourceIndex: 0})
+}
+
+// LoadTemplateFromDirectory loads a template by name from a templates filesystem
+func LoadTemplateFromDirectory(templateName string, templatesFS fs.FS) (*models.SecretTemplate, error) {
+	return NewTemplateLoader(templatesFS).loadFromSource(0, templateName)
+}
+
+// loadFromSource loads a template by name from one layer of the search path
+func (l *TemplateLoader) loadFromSource(sourceIndex int, templateName string) (*models.SecretTemplate, error) {
+	templatesFS := l.Sources[sourceIndex].FS
+
+	// Check if directory exists
+	if _, err := fs.Stat(templatesFS, "."); err != nil {
+		return nil, &errors.TemplateDirectoryNotFoundError{
+			Directory: describeFS(templatesFS),
+		}
+	}
+
+	templatePath, ok := findTemplateFile(templatesFS, templateName)
+	if !ok {
 		return nil, &errors.TemplateNotFoundError{
-			TemplateName: filePath,
+			TemplateName: templateName,
 		}
 	}
 
-	// Read file
-	data, err := os.ReadFile(filePath)
+	return l.loadTemplate(templateDocument{fsys: templatesFS, path: templatePath, sourceIndex: sourceIndex})
+}
+
+// loadTemplate parses a template document, merges any templates it extends,
+// then applies defaults and validates the result
+func (l *TemplateLoader) loadTemplate(doc templateDocument) (*models.SecretTemplate, error) {
+	node, err := l.loadNode(doc, nil)
 	if err != nil {
-		return nil, &errors.TemplateLoadError{
-			TemplateName: filePath,
-			Cause:        err,
-		}
+		return nil, err
 	}
 
-	// Parse YAML
 	var template models.SecretTemplate
-	if err := yaml.Unmarshal(data, &template); err != nil {
+	if err := node.Decode(&template); err != nil {
 		return nil, &errors.TemplateLoadError{
-			TemplateName: filePath,
+			TemplateName: doc.path,
 			Cause:        fmt.Errorf("YAML parsing failed: %w", err),
 		}
 	}
@@ -69,7 +103,7 @@ func LoadTemplateFromFile(filePath string) (*models.SecretTemplate, error) {
 	// Validate template
 	if err := template.Validate(); err != nil {
 		return nil, &errors.TemplateValidationError{
-			TemplateName: filePath,
+			TemplateName: doc.path,
 			Message:      err.Error(),
 		}
 	}
@@ -77,44 +111,52 @@ func LoadTemplateFromFile(filePath string) (*models.SecretTemplate, error) {
 	return &template, nil
 }
 
-// LoadTemplateFromDirectory loads a template by name from a templates directory
-func LoadTemplateFromDirectory(templateName string, templatesDir string) (*models.SecretTemplate, error) {
-	// Check if directory exists
-	if _, err := os.Stat(templatesDir); os.IsNotExist(err) {
-		return nil, &errors.TemplateDirectoryNotFoundError{
-			Directory: templatesDir,
-		}
-	}
-
-	// Try .yaml extension first
-	templatePath := filepath.Join(templatesDir, templateName+constants.TemplateFileExtension)
-	if _, err := os.Stat(templatePath); err == nil {
-		return LoadTemplateFromFile(templatePath)
-	}
-
-	// Try .yml extension
-	templatePath = filepath.Join(templatesDir, templateName+constants.TemplateFileExtension2)
-	if _, err := os.Stat(templatePath); err == nil {
-		return LoadTemplateFromFile(templatePath)
-	}
-
-	return nil, &errors.TemplateNotFoundError{
-		TemplateName: templateName,
-	}
-}
-
 // GetTemplate gets a template by name or file path
 func (l *TemplateLoader) GetTemplate(templateIdentifier string) (*models.SecretTemplate, error) {
+	template, _, err := l.ResolveTemplate(templateIdentifier)
+	return template, err
+}
+
+// ResolveTemplate gets a template by name or file path along with the source it was loaded from
+func (l *TemplateLoader) ResolveTemplate(templateIdentifier string) (*models.SecretTemplate, TemplateSource, error) {
 	if IsFilePath(templateIdentifier) {
 		// Direct file path
-		return LoadTemplateFromFile(templateIdentifier)
+		template, err := l.loadTemplate(templateDocument{path: templateIdentifier, sourceIndex: -1})
+		return template, TemplateSource{Name: constants.SourceFile, Path: templateIdentifier}, err
 	}
 
-	// Template name - look in default directory
-	return LoadTemplateFromDirectory(templateIdentifier, l.DefaultTemplatesDir)
+	// Template name - the highest-precedence source that has it wins
+	for i := len(l.Sources) - 1; i >= 0; i-- {
+		source := l.Sources[i]
+		if !source.HasTemplate(templateIdentifier) {
+			continue
+		}
+		template, err := l.loadFromSource(i, templateIdentifier)
+		return template, source, err
+	}
+
+	return nil, TemplateSource{}, &errors.TemplateNotFoundError{
+		TemplateName: templateIdentifier,
+	}
 }
 
 // LoadTemplate is an alias for GetTemplate for backward compatibility
 func (l *TemplateLoader) LoadTemplate(templateIdentifier string) (*models.SecretTemplate, error) {
 	return l.GetTemplate(templateIdentifier)
 }
+
+// describeFS returns a stable, human-readable name for a templates
+// filesystem: its String method if it has one, the root directory of an
+// os.DirFS, "builtin" for the embedded templates, or else its type name.
+func describeFS(templatesFS fs.FS) string {
+	if stringer, ok := templatesFS.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	if templatesFS == builtinTemplatesFS {
+		return constants.SourceBuiltin
+	}
+	if value := reflect.ValueOf(templatesFS); value.Kind() == reflect.String {
+		return value.String()
+	}
+	return fmt.Sprintf("%T", templatesFS)
+}
@@ -1,33 +1,101 @@
 package templates
 
 import (
+	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
+	"testing/fstest"
+
+	"github.com/theinfosecguy/archer/internal/models"
 )
 
 func TestNewTemplateLoader(t *testing.T) {
-	templatesDir := "testdata"
-	loader := NewTemplateLoader(templatesDir)
+	templatesFS := os.DirFS("testdata")
+	loader := NewTemplateLoader(templatesFS)
 
 	if loader == nil {
 		t.Fatal("NewTemplateLoader() returned nil")
 	}
 
-	if loader.DefaultTemplatesDir != templatesDir {
-		t.Errorf("DefaultTemplatesDir = %q, want %q", loader.DefaultTemplatesDir, templatesDir)
+	if len(loader.Sources) != 1 {
+		t.Fatalf("Sources length = %d, want 1", len(loader.Sources))
+	}
+
+	if loader.Sources[0].FS != templatesFS {
+		t.Errorf("Sources[0].FS = %v, want %v", loader.Sources[0].FS, templatesFS)
 	}
 }
 
-func TestNewTemplateLoader_EmptyDirectory(t *testing.T) {
-	loader := NewTemplateLoader("")
+func TestNewTemplateLoader_NilFallsBackToBuiltin(t *testing.T) {
+	loader := NewTemplateLoader(nil)
 
 	if loader == nil {
-		t.Fatal("NewTemplateLoader('') returned nil")
+		t.Fatal("NewTemplateLoader(nil) returned nil")
+	}
+
+	if len(loader.Sources) != 1 || loader.Sources[0].Name != "builtin" {
+		t.Fatalf("Sources = %v, want single builtin source", loader.Sources)
+	}
+
+	template, err := loader.GetTemplate("github")
+	if err != nil {
+		t.Fatalf("GetTemplate(\"github\") error = %v, want nil", err)
+	}
+
+	if template.Name != "github" {
+		t.Errorf("Name = %q, want 'github'", template.Name)
+	}
+}
+
+func TestDescribeFS(t *testing.T) {
+	tests := []struct {
+		name        string
+		templatesFS fs.FS
+		want        string
+	}{
+		{"directory", os.DirFS("testdata"), "testdata"},
+		{"builtin", BuiltinTemplatesFS(), "builtin"},
+		{"other", fstest.MapFS{}, "fstest.MapFS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describeFS(tt.templatesFS); got != tt.want {
+				t.Errorf("describeFS() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuiltinTemplatesFS_AllTemplatesValid(t *testing.T) {
+	builtinFS := BuiltinTemplatesFS()
+
+	names, err := DiscoverTemplatesInDirectory(builtinFS)
+	if err != nil {
+		t.Fatalf("DiscoverTemplatesInDirectory() error = %v, want nil", err)
+	}
+
+	if len(names) == 0 {
+		t.Fatal("No built-in templates discovered")
+	}
+
+	for _, name := range names {
+		if _, err := LoadTemplateFromDirectory(name, builtinFS); err != nil {
+			t.Errorf("Built-in template %q failed to load: %v", name, err)
+		}
+	}
+}
+
+func TestLoadTemplateFromFS_NotFound(t *testing.T) {
+	template, err := LoadTemplateFromFS(os.DirFS("testdata"), "nonexistent.yaml")
+
+	if err == nil {
+		t.Fatal("LoadTemplateFromFS() error = nil, want error")
 	}
 
-	if loader.DefaultTemplatesDir != "templates" {
-		t.Errorf("DefaultTemplatesDir = %q, want 'templates'", loader.DefaultTemplatesDir)
+	if template != nil {
+		t.Errorf("template = %v, want nil", template)
 	}
 }
 
@@ -205,9 +273,9 @@ func TestLoadTemplateFromFile_MultipartMissingVariables(t *testing.T) {
 
 func TestLoadTemplateFromDirectory_WithYamlExtension(t *testing.T) {
 	templateName := "valid_single"
-	templatesDir := "testdata"
+	templatesFS := os.DirFS("testdata")
 
-	template, err := LoadTemplateFromDirectory(templateName, templatesDir)
+	template, err := LoadTemplateFromDirectory(templateName, templatesFS)
 
 	if err != nil {
 		t.Fatalf("LoadTemplateFromDirectory() error = %v, want nil", err)
@@ -224,9 +292,9 @@ func TestLoadTemplateFromDirectory_WithYamlExtension(t *testing.T) {
 
 func TestLoadTemplateFromDirectory_WithYmlExtension(t *testing.T) {
 	templateName := "valid_multipart"
-	templatesDir := "testdata"
+	templatesFS := os.DirFS("testdata")
 
-	template, err := LoadTemplateFromDirectory(templateName, templatesDir)
+	template, err := LoadTemplateFromDirectory(templateName, templatesFS)
 
 	if err != nil {
 		t.Fatalf("LoadTemplateFromDirectory() error = %v, want nil", err)
@@ -243,9 +311,9 @@ func TestLoadTemplateFromDirectory_WithYmlExtension(t *testing.T) {
 
 func TestLoadTemplateFromDirectory_TemplateNotFound(t *testing.T) {
 	templateName := "nonexistent"
-	templatesDir := "testdata"
+	templatesFS := os.DirFS("testdata")
 
-	template, err := LoadTemplateFromDirectory(templateName, templatesDir)
+	template, err := LoadTemplateFromDirectory(templateName, templatesFS)
 
 	if err == nil {
 		t.Fatal("LoadTemplateFromDirectory() error = nil, want error")
@@ -258,9 +326,9 @@ func TestLoadTemplateFromDirectory_TemplateNotFound(t *testing.T) {
 
 func TestLoadTemplateFromDirectory_DirectoryNotFound(t *testing.T) {
 	templateName := "github"
-	templatesDir := "nonexistent_directory"
+	templatesFS := os.DirFS("nonexistent_directory")
 
-	template, err := LoadTemplateFromDirectory(templateName, templatesDir)
+	template, err := LoadTemplateFromDirectory(templateName, templatesFS)
 
 	if err == nil {
 		t.Fatal("LoadTemplateFromDirectory() error = nil, want directory not found error")
@@ -272,7 +340,7 @@ func TestLoadTemplateFromDirectory_DirectoryNotFound(t *testing.T) {
 }
 
 func TestGetTemplate_WithTemplateName(t *testing.T) {
-	loader := NewTemplateLoader("testdata")
+	loader := NewTemplateLoader(os.DirFS("testdata"))
 
 	template, err := loader.GetTemplate("valid_single")
 
@@ -290,7 +358,7 @@ func TestGetTemplate_WithTemplateName(t *testing.T) {
 }
 
 func TestGetTemplate_WithFilePath(t *testing.T) {
-	loader := NewTemplateLoader("testdata")
+	loader := NewTemplateLoader(os.DirFS("testdata"))
 
 	template, err := loader.GetTemplate("testdata/valid_multipart.yml")
 
@@ -308,7 +376,7 @@ func TestGetTemplate_WithFilePath(t *testing.T) {
 }
 
 func TestGetTemplate_NotFound(t *testing.T) {
-	loader := NewTemplateLoader("testdata")
+	loader := NewTemplateLoader(os.DirFS("testdata"))
 
 	template, err := loader.GetTemplate("nonexistent")
 
@@ -322,7 +390,7 @@ func TestGetTemplate_NotFound(t *testing.T) {
 }
 
 func TestLoadTemplate_BackwardCompatibility(t *testing.T) {
-	loader := NewTemplateLoader("testdata")
+	loader := NewTemplateLoader(os.DirFS("testdata"))
 
 	template, err := loader.LoadTemplate("valid_single")
 
@@ -387,7 +455,7 @@ error_handling:
 	}
 
 	if template.ErrorHandling.RetryDelay != 0 {
-		t.Errorf("RetryDelay = %d, want 0 (default)", template.ErrorHandling.RetryDelay)
+		t.Errorf("RetryDelay = %s, want 0 (default)", template.ErrorHandling.RetryDelay)
 	}
 }
 
@@ -427,8 +495,8 @@ func TestLoadTemplateFromFile_SuccessCriteria(t *testing.T) {
 		t.Error("StatusCode slice is empty, want at least one status code")
 	}
 
-	if template.SuccessCriteria.StatusCode[0] != 200 {
-		t.Errorf("StatusCode[0] = %d, want 200", template.SuccessCriteria.StatusCode[0])
+	if template.SuccessCriteria.StatusCode[0] != "200" {
+		t.Errorf("StatusCode[0] = %s, want 200", template.SuccessCriteria.StatusCode[0])
 	}
 
 	if len(template.SuccessCriteria.RequiredFields) != 2 {
@@ -449,15 +517,15 @@ func TestLoadTemplateFromFile_ErrorHandling(t *testing.T) {
 		t.Errorf("MaxRetries = %d, want 2", template.ErrorHandling.MaxRetries)
 	}
 
-	if template.ErrorHandling.RetryDelay != 1 {
-		t.Errorf("RetryDelay = %d, want 1", template.ErrorHandling.RetryDelay)
+	if template.ErrorHandling.RetryDelay != models.Seconds(1) {
+		t.Errorf("RetryDelay = %s, want 1s", template.ErrorHandling.RetryDelay)
 	}
 
 	if len(template.ErrorHandling.ErrorMessages) == 0 {
 		t.Error("ErrorMessages map is empty")
 	}
 
-	msg401, ok := template.ErrorHandling.ErrorMessages[401]
+	msg401, ok := template.ErrorHandling.ErrorMessages["401"]
 	if !ok {
 		t.Error("Error message for 401 not found")
 	}
@@ -495,7 +563,7 @@ func TestLoadTemplateFromFile_JSONData(t *testing.T) {
 }
 
 func TestGetTemplate_RelativePath(t *testing.T) {
-	loader := NewTemplateLoader("testdata")
+	loader := NewTemplateLoader(os.DirFS("testdata"))
 
 	template, err := loader.GetTemplate("./testdata/valid_single.yaml")
 
@@ -509,7 +577,7 @@ func TestGetTemplate_RelativePath(t *testing.T) {
 }
 
 func TestGetTemplate_AbsolutePath(t *testing.T) {
-	loader := NewTemplateLoader("testdata")
+	loader := NewTemplateLoader(os.DirFS("testdata"))
 
 	absPath, err := filepath.Abs("testdata/valid_single.yaml")
 	if err != nil {
@@ -0,0 +1,140 @@
+package templates
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/theinfosecguy/archer/internal/constants"
+	"github.com/theinfosecguy/archer/internal/logger"
+)
+
+// TemplateSource is a single layer of the template search path
+type TemplateSource struct {
+	Name string // Layer name (builtin, user, project, env, flag)
+	Path string // Directory backing the layer, empty for built-in templates
+	FS   fs.FS  // Filesystem rooted at the templates directory
+}
+
+// String returns a display-friendly description of the source
+func (s TemplateSource) String() string {
+	if s.Path == "" {
+		return s.Name
+	}
+	return s.Name + " (" + s.Path + ")"
+}
+
+// HasTemplate reports whether the source contains a template with the given name
+func (s TemplateSource) HasTemplate(templateName string) bool {
+	_, ok := findTemplateFile(s.FS, templateName)
+	return ok
+}
+
+// TemplateEntry describes a discovered template and the layer it resolved from
+type TemplateEntry struct {
+	Name     string         // Template name (file name without extension)
+	Source   TemplateSource // Layer the template resolved from
+	Shadowed []string       // Names of lower-precedence layers overridden by Source
+}
+
+// BuiltinSource returns the search path layer for templates embedded in the binary
+func BuiltinSource() TemplateSource {
+	return TemplateSource{
+		Name: constants.SourceBuiltin,
+		FS:   BuiltinTemplatesFS(),
+	}
+}
+
+// DirectorySource returns a search path layer backed by a directory on disk
+func DirectorySource(name string, dir string) TemplateSource {
+	return TemplateSource{
+		Name: name,
+		Path: dir,
+		FS:   os.DirFS(dir),
+	}
+}
+
+// UserTemplatesDir returns the per-user templates directory following the XDG convention
+func UserTemplatesDir() string {
+	configHome := os.Getenv(constants.EnvXDGConfigHome)
+	if configHome == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		configHome = filepath.Join(homeDir, constants.UserConfigDir)
+	}
+	return filepath.Join(configHome, filepath.FromSlash(constants.UserTemplatesSubdir))
+}
+
+// DefaultSearchPath builds the ordered template search path: built-in templates,
+// the user config directory, the project-local directory, ARCHER_TEMPLATES_PATH
+// entries, and finally any explicitly provided directories.
+// Directories that do not exist are skipped.
+func DefaultSearchPath(extraDirs []string) []TemplateSource {
+	sources := []TemplateSource{BuiltinSource()}
+
+	addDir := func(name string, dir string) {
+		if dir == "" {
+			return
+		}
+		info, err := os.Stat(dir)
+		if err != nil || !info.IsDir() {
+			logger.Debug("Skipping %s templates directory '%s': not found", name, dir)
+			return
+		}
+		sources = append(sources, DirectorySource(name, dir))
+	}
+
+	addDir(constants.SourceUser, UserTemplatesDir())
+	addDir(constants.SourceProject, filepath.FromSlash(constants.ProjectTemplatesDir))
+
+	for _, dir := range filepath.SplitList(os.Getenv(constants.EnvTemplatesPath)) {
+		addDir(constants.SourceEnv, strings.TrimSpace(dir))
+	}
+
+	for _, dir := range extraDirs {
+		addDir(constants.SourceFlag, dir)
+	}
+
+	return sources
+}
+
+// NewSearchPathLoader creates a template loader using the default search path
+// with additional directories layered on top
+func NewSearchPathLoader(extraDirs []string) *TemplateLoader {
+	return NewLayeredTemplateLoader(DefaultSearchPath(extraDirs)...)
+}
+
+// ListTemplates lists every template on the search path, resolved to the
+// highest-precedence layer that provides it
+func (l *TemplateLoader) ListTemplates() []TemplateEntry {
+	entries := make(map[string]*TemplateEntry)
+
+	for _, source := range l.Sources {
+		names, err := DiscoverTemplatesInDirectory(source.FS)
+		if err != nil {
+			continue
+		}
+		for _, name := range names {
+			if existing, ok := entries[name]; ok {
+				existing.Shadowed = append(existing.Shadowed, existing.Source.Name)
+				existing.Source = source
+				continue
+			}
+			entries[name] = &TemplateEntry{Name: name, Source: source}
+		}
+	}
+
+	result := make([]TemplateEntry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, *entry)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
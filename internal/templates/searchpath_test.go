@@ -0,0 +1,161 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const overrideTemplateYAML = `name: github
+description: GitHub Enterprise token validation
+api_url: https://github.example.com/api/v3/user
+method: GET
+mode: single
+
+request:
+  headers:
+    Authorization: Bearer ${SECRET}
+  timeout: 10
+
+success_criteria:
+  status_code: [200]
+`
+
+func writeOverrideTemplate(t *testing.T, dir string, fileName string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create %s: %v", dir, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, fileName), []byte(overrideTemplateYAML), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", fileName, err)
+	}
+}
+
+func TestResolveTemplate_LaterSourceOverridesBuiltin(t *testing.T) {
+	tempDir := t.TempDir()
+	writeOverrideTemplate(t, tempDir, "github.yaml")
+
+	loader := NewLayeredTemplateLoader(BuiltinSource(), DirectorySource("flag", tempDir))
+
+	template, source, err := loader.ResolveTemplate("github")
+	if err != nil {
+		t.Fatalf("ResolveTemplate() error = %v, want nil", err)
+	}
+
+	if source.Name != "flag" {
+		t.Errorf("source.Name = %q, want 'flag'", source.Name)
+	}
+
+	if template.APIURL != "https://github.example.com/api/v3/user" {
+		t.Errorf("APIURL = %q, want override URL", template.APIURL)
+	}
+}
+
+func TestResolveTemplate_FallsBackToLowerSource(t *testing.T) {
+	tempDir := t.TempDir()
+	writeOverrideTemplate(t, tempDir, "github.yaml")
+
+	loader := NewLayeredTemplateLoader(BuiltinSource(), DirectorySource("flag", tempDir))
+
+	_, source, err := loader.ResolveTemplate("stripe")
+	if err != nil {
+		t.Fatalf("ResolveTemplate() error = %v, want nil", err)
+	}
+
+	if source.Name != "builtin" {
+		t.Errorf("source.Name = %q, want 'builtin'", source.Name)
+	}
+}
+
+func TestResolveTemplate_FilePath(t *testing.T) {
+	loader := NewLayeredTemplateLoader(BuiltinSource())
+
+	_, source, err := loader.ResolveTemplate("testdata/valid_single.yaml")
+	if err != nil {
+		t.Fatalf("ResolveTemplate() error = %v, want nil", err)
+	}
+
+	if source.Name != "file" {
+		t.Errorf("source.Name = %q, want 'file'", source.Name)
+	}
+}
+
+func TestResolveTemplate_NotFound(t *testing.T) {
+	loader := NewLayeredTemplateLoader(BuiltinSource())
+
+	template, _, err := loader.ResolveTemplate("nonexistent")
+	if err == nil {
+		t.Fatal("ResolveTemplate() error = nil, want not found error")
+	}
+
+	if template != nil {
+		t.Errorf("template = %v, want nil", template)
+	}
+}
+
+func TestListTemplates_RecordsShadowedSources(t *testing.T) {
+	userDir := t.TempDir()
+	flagDir := t.TempDir()
+	writeOverrideTemplate(t, userDir, "github.yaml")
+	writeOverrideTemplate(t, flagDir, "github.yml")
+
+	loader := NewLayeredTemplateLoader(
+		BuiltinSource(),
+		DirectorySource("user", userDir),
+		DirectorySource("flag", flagDir),
+	)
+
+	var found bool
+	for _, entry := range loader.ListTemplates() {
+		if entry.Name != "github" {
+			continue
+		}
+		found = true
+
+		if entry.Source.Name != "flag" {
+			t.Errorf("Source.Name = %q, want 'flag'", entry.Source.Name)
+		}
+
+		if len(entry.Shadowed) != 2 || entry.Shadowed[0] != "builtin" || entry.Shadowed[1] != "user" {
+			t.Errorf("Shadowed = %v, want [builtin user]", entry.Shadowed)
+		}
+	}
+
+	if !found {
+		t.Error("github template not listed")
+	}
+}
+
+func TestDefaultSearchPath_LayerOrder(t *testing.T) {
+	configHome := t.TempDir()
+	envDir := t.TempDir()
+	flagDir := t.TempDir()
+	writeOverrideTemplate(t, filepath.Join(configHome, "archer", "templates"), "github.yaml")
+
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("ARCHER_TEMPLATES_PATH", envDir+string(os.PathListSeparator)+filepath.Join(envDir, "missing"))
+
+	sources := DefaultSearchPath([]string{flagDir})
+
+	expected := []string{"builtin", "user", "env", "flag"}
+	if len(sources) != len(expected) {
+		t.Fatalf("Got %d sources %v, want %v", len(sources), sources, expected)
+	}
+
+	for i, source := range sources {
+		if source.Name != expected[i] {
+			t.Errorf("sources[%d].Name = %q, want %q", i, source.Name, expected[i])
+		}
+	}
+}
+
+func TestUserTemplatesDir_XDGConfigHome(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+
+	dir := UserTemplatesDir()
+
+	want := filepath.Join("/tmp/xdg", "archer", "templates")
+	if dir != want {
+		t.Errorf("UserTemplatesDir() = %q, want %q", dir, want)
+	}
+}
@@ -1,7 +1,9 @@
 package validator
 
 import (
+	"context"
 	"fmt"
+	"io/fs"
 	"strings"
 
 	"github.com/theinfosecguy/archer/internal/constants"
@@ -12,70 +14,112 @@ import (
 	"github.com/theinfosecguy/archer/internal/variables"
 )
 
-// SecretValidator validates secrets using configured templates
+// SecretValidator validates secrets using configured templates. It is safe
+// for concurrent use, so one validator can serve many validations.
 type SecretValidator struct {
 	TemplateLoader *templates.TemplateLoader
 	HTTPClient     *http.Client
 }
 
-// NewSecretValidator creates a new secret validator
-func NewSecretValidator(templatesDir string) *SecretValidator {
-	logger.Info("Validator initialized with templates directory: %s", templatesDir)
+// NewSecretValidator creates a new secret validator. templatesFS is passed to
+// templates.NewTemplateLoader, so nil selects the built-in templates.
+func NewSecretValidator(templatesFS fs.FS) *SecretValidator {
+	return NewSecretValidatorWithLoader(templates.NewTemplateLoader(templatesFS))
+}
+
+// NewSecretValidatorWithLoader creates a new secret validator using an existing template loader
+func NewSecretValidatorWithLoader(loader *templates.TemplateLoader) *SecretValidator {
+	logger.Info("Validator initialized with template sources: %v", loader.Sources)
 	return &SecretValidator{
-		TemplateLoader: templates.NewTemplateLoader(templatesDir),
+		TemplateLoader: loader,
 		HTTPClient:     http.NewClient(),
 	}
 }
 
 // ValidateSecret validates a secret using the specified template (single mode)
-func (v *SecretValidator) ValidateSecret(templateName string, secret string) (*models.ValidationResult, error) {
+func (v *SecretValidator) ValidateSecret(ctx context.Context, templateName string, secret string) (*models.ValidationResult, error) {
+	return v.ValidateSecretWithVariables(ctx, templateName, secret, nil)
+}
+
+// ValidateSecretWithVariables validates a secret using the specified template (single mode),
+// overriding the defaults of the template's optional variables
+func (v *SecretValidator) ValidateSecretWithVariables(ctx context.Context, templateName string, secret string, optionalVars map[string]string) (*models.ValidationResult, error) {
 	logger.Info("Starting secret validation for template '%s' (mode: single)", templateName)
 
 	template, err := v.TemplateLoader.GetTemplate(templateName)
 	if err != nil {
-		logger.Info("Validation failed: template '%s' not found in templates directory", templateName)
+		logger.Info("Validation failed: template '%s' not found", templateName)
 		return &models.ValidationResult{
-			Valid: false,
-			Error: fmt.Sprintf(constants.TemplateNotFound, templateName),
+			Valid:  false,
+			Status: constants.StatusError,
+			Error:  fmt.Sprintf(constants.TemplateNotFound, templateName),
 		}, nil
 	}
 
 	if template.Mode != constants.ModeSingle {
 		logger.Info("Template '%s' is not in single mode", templateName)
 		return &models.ValidationResult{
-			Valid: false,
-			Error: fmt.Sprintf("Template '%s' is not in single mode", templateName),
+			Valid:  false,
+			Status: constants.StatusError,
+			Error:  fmt.Sprintf("Template '%s' is not in single mode", templateName),
 		}, nil
 	}
 
 	logger.Debug("Loaded template '%s': %s", template.Name, template.Description)
 
+	// Only optional variables may accompany the secret in single mode
+	if unexpected := variables.FindUnexpectedVariables(nil, template.OptionalVariables, optionalVars); len(unexpected) > 0 {
+		logger.Info("Unexpected variables: %s", strings.Join(unexpected, ", "))
+		return &models.ValidationResult{
+			Valid:  false,
+			Status: constants.StatusError,
+			Error:  fmt.Sprintf(constants.UnexpectedVariables, strings.Join(unexpected, ", ")),
+		}, nil
+	}
+
+	// Reject malformed secrets before they are sent anywhere
+	if template.SecretFormat != nil {
+		if err := template.SecretFormat.Check(secret); err != nil {
+			logger.Info("Secret format check failed: %v", err)
+			return &models.ValidationResult{
+				Valid:   false,
+				Status:  constants.StatusInvalid,
+				Outcome: constants.OutcomeFormatInvalid,
+				Error:   fmt.Sprintf(constants.SecretFormatInvalid, err),
+			}, nil
+		}
+	}
+
 	// For single mode, create variables map with SECRET
-	variablesMap := map[string]string{
-		constants.SecretVariableName: secret,
+	variablesMap := make(map[string]string, len(optionalVars)+1)
+	for name, value := range optionalVars {
+		variablesMap[name] = value
 	}
+	variablesMap[constants.SecretVariableName] = secret
 
-	return v.validateWithTemplate(template, variablesMap)
+	return v.validateWithTemplate(ctx, template, variablesMap)
 }
 
 // ValidateSecretMultipart validates secrets using the specified multipart template
-func (v *SecretValidator) ValidateSecretMultipart(templateName string, variablesMap map[string]string) (*models.ValidationResult, error) {
+func (v *SecretValidator) ValidateSecretMultipart(ctx context.Context, templateName string, variablesMap map[string]string) (*models.ValidationResult, error) {
 	logger.Info("Starting secret validation for template '%s' (mode: multipart)", templateName)
 
 	template, err := v.TemplateLoader.GetTemplate(templateName)
 	if err != nil {
-		logger.Info("Validation failed: template '%s' not found in templates directory", templateName)
+		logger.Info("Validation failed: template '%s' not found", templateName)
 		return &models.ValidationResult{
-			Valid: false,
-			Error: fmt.Sprintf(constants.TemplateNotFound, templateName),
+			Valid:  false,
+			Status: constants.StatusError,
+			Error:  fmt.Sprintf(constants.TemplateNotFound, templateName),
 		}, nil
 	}
 
 	if template.Mode != constants.ModeMultipart {
 		logger.Info("Template '%s' is not in multipart mode", templateName)
 		return &models.ValidationResult{
-			Valid: false,
-			Error: fmt.Sprintf("Template '%s' is not in multipart mode", templateName),
+			Valid:  false,
+			Status: constants.StatusError,
+			Error:  fmt.Sprintf("Template '%s' is not in multipart mode", templateName),
 		}, nil
 	}
 
@@ -87,15 +131,86 @@ func (v *SecretValidator) ValidateSecretMultipart(templateName string, variables
 		errorMsg := fmt.Sprintf(constants.MissingRequiredVariables, strings.Join(missingVars, ", "))
 		logger.Info("Missing required variables: %s", strings.Join(missingVars, ", "))
 		return &models.ValidationResult{
-			Valid: false,
-			Error: errorMsg,
+			Valid:  false,
+			Status: constants.StatusError,
+			Error:  errorMsg,
 		}, nil
 	}
 
-	return v.validateWithTemplate(template, variablesMap)
+	if unexpected := variables.FindUnexpectedVariables(template.RequiredVariables, template.OptionalVariables, variablesMap); len(unexpected) > 0 {
+		logger.Info("Unexpected variables: %s", strings.Join(unexpected, ", "))
+		return &models.ValidationResult{
+			Valid:  false,
+			Status: constants.StatusError,
+			Error:  fmt.Sprintf(constants.UnexpectedVariables, strings.Join(unexpected, ", ")),
+		}, nil
+	}
+
+	return v.validateWithTemplate(ctx, template, variablesMap)
+}
+
+// ValidateSecretAuto validates a secret against ranked candidate templates in turn
+// until one reports valid. It returns that result and template; when none is valid it
+// returns the first inconclusive result, so the secret is retried later rather than
+// reported as revoked, or else the result from the top-ranked candidate. Once ctx
+// is done no further candidates are tried.
+func (v *SecretValidator) ValidateSecretAuto(ctx context.Context, candidates []templates.Candidate, secret string) (*models.ValidationResult, *templates.Candidate, error) {
+	if len(candidates) == 0 {
+		return &models.ValidationResult{
+			Valid:  false,
+			Status: constants.StatusError,
+			Error:  constants.NoTemplateMatched,
+		}, nil, nil
+	}
+
+	attempts := make([]models.TemplateAttempt, 0, len(candidates))
+	results := make([]*models.ValidationResult, 0, len(candidates))
+	for i := range candidates {
+		candidate := &candidates[i]
+		logger.Info("Trying candidate template '%s' (score %d)", candidate.Name, candidate.Score)
+
+		result, err := v.ValidateSecret(ctx, candidate.Name, secret)
+		if err != nil {
+			return nil, nil, err
+		}
+		attempts = append(attempts, models.TemplateAttempt{
+			Template: candidate.Name,
+			Status:   result.Status,
+			Outcome:  result.Outcome,
+		})
+		results = append(results, result)
+
+		if result.Valid {
+			result.Attempts = attempts
+			return result, candidate, nil
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	chosen := 0
+	for i, result := range results {
+		if result.Status == constants.StatusInconclusive {
+			chosen = i
+			break
+		}
+	}
+	results[chosen].Attempts = attempts
+	return results[chosen], &candidates[chosen], nil
 }
 
-func (v *SecretValidator) validateWithTemplate(template *models.SecretTemplate, vars map[string]string) (*models.ValidationResult, error) {
+// ProbeCapabilities runs the capability probes defined by the template and returns the capability matrix.
+// Probes reuse the variables of the validation that produced result, so steps are not repeated.
+func (v *SecretValidator) ProbeCapabilities(ctx context.Context, template *models.SecretTemplate, result *models.ValidationResult) []models.CapabilityResult {
+	logger.Info("Probing %d capabilities for template '%s'", len(template.Capabilities), template.Name)
+	return v.HTTPClient.ProbeCapabilities(ctx, template, result.Variables)
+}
+
+func (v *SecretValidator) validateWithTemplate(ctx context.Context, template *models.SecretTemplate, vars map[string]string) (*models.ValidationResult, error) {
+	// Fill in defaults for optional variables that were not provided
+	vars = variables.ApplyDefaults(template.OptionalVariables, vars)
+
 	// Delegate to HTTP client for request execution
-	return v.HTTPClient.ExecuteRequest(template, vars)
+	return v.HTTPClient.ExecuteRequest(ctx, template, vars)
 }
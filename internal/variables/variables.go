@@ -3,6 +3,9 @@ package variables
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/theinfosecguy/archer/internal/constants"
@@ -39,21 +42,77 @@ func MaskVariables(content string) string {
 	})
 }
 
+// MaskSecretVariables masks the placeholders in content whose variables are
+// secret and injects the values of the rest. A nil isSecret masks every variable.
+func MaskSecretVariables(content string, variables map[string]string, isSecret func(string) bool) string {
+	if isSecret == nil {
+		return MaskVariables(content)
+	}
+	if content == "" {
+		return content
+	}
+
+	return constants.VariablePattern.ReplaceAllStringFunc(content, func(match string) string {
+		varName := match[2 : len(match)-1] // Remove ${ and }
+		if isSecret(varName) {
+			return fmt.Sprintf("%s%s%s", constants.MaskedVariablePrefix, varName, constants.MaskedVariableSuffix)
+		}
+		if value, ok := variables[varName]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// MaskSecretValues replaces the values of secret variables found in content,
+// including their URL-escaped forms, with the same mask used for placeholders.
+// It is used for text that already has values injected, such as transport errors
+// that quote the request URL. A nil isSecret masks every variable.
+func MaskSecretValues(content string, variables map[string]string, isSecret func(string) bool) string {
+	for _, name := range sortedByValueLength(variables) {
+		value := variables[name]
+		if value == "" || (isSecret != nil && !isSecret(name)) {
+			continue
+		}
+		mask := fmt.Sprintf("%s%s%s", constants.MaskedVariablePrefix, name, constants.MaskedVariableSuffix)
+		for _, form := range []string{value, url.QueryEscape(value), url.PathEscape(value)} {
+			content = strings.ReplaceAll(content, form, mask)
+		}
+	}
+	return content
+}
+
+// sortedByValueLength returns variable names with the longest values first, so
+// a value containing another is masked whole
+func sortedByValueLength(variables map[string]string) []string {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if len(variables[names[i]]) != len(variables[names[j]]) {
+			return len(variables[names[i]]) > len(variables[names[j]])
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
 // ProcessHeaders processes headers for both request use and masked logging
-func ProcessHeaders(headers map[string]string, variables map[string]string) (map[string]string, map[string]string) {
+func ProcessHeaders(headers map[string]string, variables map[string]string, isSecret func(string) bool) (map[string]string, map[string]string) {
 	requestHeaders := make(map[string]string)
 	maskedHeaders := make(map[string]string)
 
 	for key, value := range headers {
 		requestHeaders[key] = InjectVariables(value, variables)
-		maskedHeaders[key] = MaskVariables(value)
+		maskedHeaders[key] = MaskSecretVariables(value, variables, isSecret)
 	}
 
 	return requestHeaders, maskedHeaders
 }
 
 // ProcessQueryParams processes query parameters for both request use and masked logging
-func ProcessQueryParams(queryParams map[string]string, variables map[string]string) (map[string]string, map[string]string) {
+func ProcessQueryParams(queryParams map[string]string, variables map[string]string, isSecret func(string) bool) (map[string]string, map[string]string) {
 	if queryParams == nil {
 		return nil, nil
 	}
@@ -63,61 +122,61 @@ func ProcessQueryParams(queryParams map[string]string, variables map[string]stri
 
 	for key, value := range queryParams {
 		requestParams[key] = InjectVariables(value, variables)
-		maskedParams[key] = MaskVariables(value)
+		maskedParams[key] = MaskSecretVariables(value, variables, isSecret)
 	}
 
 	return requestParams, maskedParams
 }
 
 // ProcessURL processes URL for both request use and masked logging
-func ProcessURL(url string, variables map[string]string) (string, string) {
+func ProcessURL(url string, variables map[string]string, isSecret func(string) bool) (string, string) {
 	requestURL := InjectVariables(url, variables)
-	maskedURL := MaskVariables(url)
+	maskedURL := MaskSecretVariables(url, variables, isSecret)
 	return requestURL, maskedURL
 }
 
 // ProcessData processes data string for both request use and masked logging
-func ProcessData(data *string, variables map[string]string) (*string, *string) {
+func ProcessData(data *string, variables map[string]string, isSecret func(string) bool) (*string, *string) {
 	if data == nil {
 		return nil, nil
 	}
 
 	requestData := InjectVariables(*data, variables)
-	maskedData := MaskVariables(*data)
+	maskedData := MaskSecretVariables(*data, variables, isSecret)
 	return &requestData, &maskedData
 }
 
 // ProcessJSONData processes JSON data for both request use and masked logging
-func ProcessJSONData(jsonData map[string]any, variables map[string]string) (map[string]any, map[string]any) {
+func ProcessJSONData(jsonData map[string]any, variables map[string]string, isSecret func(string) bool) (map[string]any, map[string]any) {
 	if jsonData == nil {
 		return nil, nil
 	}
 
-	requestData := processJSONRecursive(jsonData, variables, false).(map[string]any)
-	maskedData := processJSONRecursive(jsonData, variables, true).(map[string]any)
+	requestData := processJSONRecursive(jsonData, variables, false, isSecret).(map[string]any)
+	maskedData := processJSONRecursive(jsonData, variables, true, isSecret).(map[string]any)
 
 	return requestData, maskedData
 }
 
-func processJSONRecursive(obj any, variables map[string]string, mask bool) any {
+func processJSONRecursive(obj any, variables map[string]string, mask bool, isSecret func(string) bool) any {
 	switch v := obj.(type) {
 	case string:
 		if mask {
-			return MaskVariables(v)
+			return MaskSecretVariables(v, variables, isSecret)
 		}
 		return InjectVariables(v, variables)
 
 	case map[string]any:
 		result := make(map[string]any)
 		for key, value := range v {
-			result[key] = processJSONRecursive(value, variables, mask)
+			result[key] = processJSONRecursive(value, variables, mask, isSecret)
 		}
 		return result
 
 	case []any:
 		result := make([]any, len(v))
 		for i, item := range v {
-			result[i] = processJSONRecursive(item, variables, mask)
+			result[i] = processJSONRecursive(item, variables, mask, isSecret)
 		}
 		return result
 
@@ -168,6 +227,34 @@ func ValidateVariablesProvided(requiredVariables []string, providedVariables map
 	return missing
 }
 
+// ApplyDefaults returns the provided variables with defaults filled in for
+// optional variables that were not provided or are blank
+func ApplyDefaults(optionalVariables map[string]string, providedVariables map[string]string) map[string]string {
+	result := make(map[string]string, len(providedVariables)+len(optionalVariables))
+	for name, value := range providedVariables {
+		result[name] = value
+	}
+	for name, defaultValue := range optionalVariables {
+		if strings.TrimSpace(result[name]) == "" {
+			result[name] = defaultValue
+		}
+	}
+	return result
+}
+
+// FindUnexpectedVariables returns the provided variables that are neither required nor optional, sorted by name
+func FindUnexpectedVariables(requiredVariables []string, optionalVariables map[string]string, providedVariables map[string]string) []string {
+	unexpected := make([]string, 0)
+	for name := range providedVariables {
+		if _, ok := optionalVariables[name]; ok || slices.Contains(requiredVariables, name) {
+			continue
+		}
+		unexpected = append(unexpected, name)
+	}
+	sort.Strings(unexpected)
+	return unexpected
+}
+
 // ParseVarArgs parses --var key=value arguments into a dictionary
 func ParseVarArgs(varArgs []string) (map[string]string, error) {
 	variables := make(map[string]string)
@@ -206,7 +293,7 @@ func FormatVarNameForCLI(upperSnakeName string) string {
 
 // MarshalJSONWithVariables marshals data to JSON string with variable injection
 func MarshalJSONWithVariables(data map[string]any, variables map[string]string) (string, error) {
-	processed := processJSONRecursive(data, variables, false)
+	processed := processJSONRecursive(data, variables, false, nil)
 	bytes, err := json.Marshal(processed)
 	if err != nil {
 		return "", err
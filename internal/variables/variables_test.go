@@ -430,7 +430,7 @@ func TestProcessJSONData_SimpleObject(t *testing.T) {
 		"STRIPE_SECRET": "whsec_def456",
 	}
 
-	requestData, maskedData := ProcessJSONData(jsonData, variables)
+	requestData, maskedData := ProcessJSONData(jsonData, variables, nil)
 
 	if requestData["api_key"] != "sk_live_abc123" {
 		t.Errorf("requestData api_key = %v, want sk_live_abc123", requestData["api_key"])
@@ -457,7 +457,7 @@ func TestProcessJSONData_NestedObject(t *testing.T) {
 		"DB_HOST":           "localhost",
 	}
 
-	requestData, maskedData := ProcessJSONData(jsonData, variables)
+	requestData, maskedData := ProcessJSONData(jsonData, variables, nil)
 
 	credentials := requestData["credentials"].(map[string]any)
 	if credentials["password"] != "secure_pass_123" {
@@ -485,7 +485,7 @@ func TestProcessJSONData_Array(t *testing.T) {
 		"TOKEN_3": "ghp_token_gamma",
 	}
 
-	requestData, maskedData := ProcessJSONData(jsonData, variables)
+	requestData, maskedData := ProcessJSONData(jsonData, variables, nil)
 
 	tokens := requestData["tokens"].([]any)
 	if tokens[0] != "ghp_token_alpha" {
@@ -510,7 +510,7 @@ func TestProcessJSONData_MixedTypes(t *testing.T) {
 		"OPENAI_KEY": "sk-proj-abc123",
 	}
 
-	requestData, _ := ProcessJSONData(jsonData, variables)
+	requestData, _ := ProcessJSONData(jsonData, variables, nil)
 
 	if requestData["api_key"] != "sk-proj-abc123" {
 		t.Errorf("api_key = %v, want sk-proj-abc123", requestData["api_key"])
@@ -535,7 +535,7 @@ func TestProcessJSONData_EmptyObject(t *testing.T) {
 		"API_KEY": "sk_key",
 	}
 
-	requestData, maskedData := ProcessJSONData(jsonData, variables)
+	requestData, maskedData := ProcessJSONData(jsonData, variables, nil)
 
 	if len(requestData) != 0 {
 		t.Errorf("requestData length = %d, want 0", len(requestData))
@@ -555,7 +555,7 @@ func TestProcessJSONData_NullValues(t *testing.T) {
 		"API_TOKEN": "token_value",
 	}
 
-	requestData, _ := ProcessJSONData(jsonData, variables)
+	requestData, _ := ProcessJSONData(jsonData, variables, nil)
 
 	if requestData["optional_field"] != nil {
 		t.Errorf("optional_field = %v, want nil", requestData["optional_field"])
@@ -584,7 +584,7 @@ func TestProcessJSONData_ArrayOfObjects(t *testing.T) {
 		"STAGING_URL": "https://api.staging.aws.acme.io",
 	}
 
-	requestData, maskedData := ProcessJSONData(jsonData, variables)
+	requestData, maskedData := ProcessJSONData(jsonData, variables, nil)
 
 	servers := requestData["servers"].([]any)
 	prodServer := servers[0].(map[string]any)
@@ -607,7 +607,7 @@ func TestProcessJSONData_NilInput(t *testing.T) {
 		"API_KEY": "sk_key",
 	}
 
-	requestData, maskedData := ProcessJSONData(jsonData, variables)
+	requestData, maskedData := ProcessJSONData(jsonData, variables, nil)
 
 	if requestData != nil {
 		t.Errorf("requestData = %v, want nil", requestData)
@@ -702,7 +702,7 @@ func TestProcessHeaders(t *testing.T) {
 		"CUSTOM_HEADER": "custom-value",
 	}
 
-	requestHeaders, maskedHeaders := ProcessHeaders(headers, variables)
+	requestHeaders, maskedHeaders := ProcessHeaders(headers, variables, nil)
 
 	if requestHeaders["Authorization"] != "Bearer test_fake_xoxb_1234_5678_abcd" {
 		t.Errorf("Authorization header = %q, want Bearer test_fake_xoxb_1234_5678_abcd", requestHeaders["Authorization"])
@@ -727,7 +727,7 @@ func TestProcessQueryParams(t *testing.T) {
 		"API_KEY": "abc123def456",
 	}
 
-	requestParams, maskedParams := ProcessQueryParams(queryParams, variables)
+	requestParams, maskedParams := ProcessQueryParams(queryParams, variables, nil)
 
 	if requestParams["key"] != "abc123def456" {
 		t.Errorf("key param = %q, want abc123def456", requestParams["key"])
@@ -748,7 +748,7 @@ func TestProcessQueryParams_NilInput(t *testing.T) {
 		"API_KEY": "key123",
 	}
 
-	requestParams, maskedParams := ProcessQueryParams(queryParams, variables)
+	requestParams, maskedParams := ProcessQueryParams(queryParams, variables, nil)
 
 	if requestParams != nil {
 		t.Errorf("requestParams = %v, want nil", requestParams)
@@ -766,7 +766,7 @@ func TestProcessURL(t *testing.T) {
 		"USER_ID":  "12345",
 	}
 
-	requestURL, maskedURL := ProcessURL(url, variables)
+	requestURL, maskedURL := ProcessURL(url, variables, nil)
 
 	expectedRequest := "https://api.github.com/api/v1/users/12345"
 	expectedMasked := "***BASE_URL***/api/v1/users/***USER_ID***"
@@ -780,6 +780,44 @@ func TestProcessURL(t *testing.T) {
 	}
 }
 
+func TestProcessURL_NonSecretVariables(t *testing.T) {
+	url := "${BASE_URL}/api/v1/users/${USER_ID}?token=${TOKEN}"
+	variables := map[string]string{
+		"BASE_URL": "https://api.github.com",
+		"USER_ID":  "12345",
+		"TOKEN":    "secret-token",
+	}
+	isSecret := func(name string) bool { return name == "TOKEN" }
+
+	_, maskedURL := ProcessURL(url, variables, isSecret)
+
+	expectedMasked := "https://api.github.com/api/v1/users/12345?token=***TOKEN***"
+	if maskedURL != expectedMasked {
+		t.Errorf("maskedURL = %q, want %q", maskedURL, expectedMasked)
+	}
+}
+
+func TestProcessJSONData_NonSecretVariables(t *testing.T) {
+	jsonData := map[string]any{
+		"site":  "${SITE}",
+		"token": "${TOKEN}",
+	}
+	variables := map[string]string{
+		"SITE":  "datadoghq.eu",
+		"TOKEN": "secret-token",
+	}
+	isSecret := func(name string) bool { return name != "SITE" }
+
+	_, maskedData := ProcessJSONData(jsonData, variables, isSecret)
+
+	if maskedData["site"] != "datadoghq.eu" {
+		t.Errorf("maskedData[site] = %v, want %q", maskedData["site"], "datadoghq.eu")
+	}
+	if maskedData["token"] != "***TOKEN***" {
+		t.Errorf("maskedData[token] = %v, want %q", maskedData["token"], "***TOKEN***")
+	}
+}
+
 func TestProcessData(t *testing.T) {
 	data := "grant_type=client_credentials&client_id=${CLIENT_ID}&client_secret=${CLIENT_SECRET}"
 	variables := map[string]string{
@@ -787,7 +825,7 @@ func TestProcessData(t *testing.T) {
 		"CLIENT_SECRET": "app_client_secret",
 	}
 
-	requestData, maskedData := ProcessData(&data, variables)
+	requestData, maskedData := ProcessData(&data, variables, nil)
 
 	expectedRequest := "grant_type=client_credentials&client_id=app_client_id&client_secret=app_client_secret"
 	expectedMasked := "grant_type=client_credentials&client_id=***CLIENT_ID***&client_secret=***CLIENT_SECRET***"
@@ -807,7 +845,7 @@ func TestProcessData_NilInput(t *testing.T) {
 		"API_KEY": "key123",
 	}
 
-	requestData, maskedData := ProcessData(data, variables)
+	requestData, maskedData := ProcessData(data, variables, nil)
 
 	if requestData != nil {
 		t.Errorf("requestData = %v, want nil", requestData)
@@ -857,3 +895,50 @@ func containsSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestApplyDefaults(t *testing.T) {
+	optional := map[string]string{"SITE": "datadoghq.com", "REGION": "us"}
+	provided := map[string]string{"SECRET": "abc", "SITE": "datadoghq.eu", "REGION": "  "}
+
+	result := ApplyDefaults(optional, provided)
+
+	expected := map[string]string{"SECRET": "abc", "SITE": "datadoghq.eu", "REGION": "us"}
+	for key, want := range expected {
+		if result[key] != want {
+			t.Errorf("result[%q] = %q, want %q", key, result[key], want)
+		}
+	}
+
+	if provided["REGION"] != "  " {
+		t.Error("ApplyDefaults() modified the provided map")
+	}
+}
+
+func TestFindUnexpectedVariables(t *testing.T) {
+	required := []string{"API_TOKEN"}
+	optional := map[string]string{"BASE_URL": "https://gitlab.com"}
+	provided := map[string]string{"API_TOKEN": "x", "BASE_URL": "y", "BASE_ULR": "z", "EXTRA": "w"}
+
+	unexpected := FindUnexpectedVariables(required, optional, provided)
+
+	if len(unexpected) != 2 || unexpected[0] != "BASE_ULR" || unexpected[1] != "EXTRA" {
+		t.Errorf("FindUnexpectedVariables() = %v, want [BASE_ULR EXTRA]", unexpected)
+	}
+}
+
+func TestMaskSecretValues(t *testing.T) {
+	vars := map[string]string{"SECRET": "a+b/c", "REGION": "eu-west-1"}
+	isSecret := func(name string) bool { return name == "SECRET" }
+
+	content := `Get "https://eu-west-1.example.com/a%2Bb%2Fc?key=a%2Bb%2Fc": dial tcp: refused (a+b/c)`
+	got := MaskSecretValues(content, vars, isSecret)
+
+	want := `Get "https://eu-west-1.example.com/***SECRET***?key=***SECRET***": dial tcp: refused (***SECRET***)`
+	if got != want {
+		t.Errorf("MaskSecretValues() = %q, want %q", got, want)
+	}
+
+	if got := MaskSecretValues("eu-west-1", vars, nil); got != "***REGION***" {
+		t.Errorf("MaskSecretValues() with nil isSecret = %q, want every value masked", got)
+	}
+}